@@ -28,10 +28,21 @@ const (
 )
 
 // AlertSpec defines an alerting rule for events involving a list of objects.
+// +kubebuilder:validation:XValidation:rule="(has(self.providerRef) && size(self.providerRef.name) > 0) || (has(self.providerRefs) && size(self.providerRefs) > 0)", message="at least one of spec.providerRef or spec.providerRefs must be specified"
 type AlertSpec struct {
 	// ProviderRef specifies which Provider this Alert should use.
-	// +required
-	ProviderRef meta.LocalObjectReference `json:"providerRef"`
+	// Either ProviderRef or ProviderRefs must be specified.
+	// +optional
+	ProviderRef *meta.LocalObjectReference `json:"providerRef,omitempty"`
+
+	// ProviderRefs specifies a list of Providers this Alert should use,
+	// in addition to the ProviderRef, with optional per-Provider overrides.
+	// The notifications are dispatched to all the Providers in parallel.
+	// +kubebuilder:validation:MaxItems:=32
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ProviderRefs []AlertProviderReference `json:"providerRefs,omitempty"`
 
 	// EventSeverity specifies how to filter events based on severity.
 	// If set to 'info' no events will be filtered.
@@ -77,6 +88,27 @@ type AlertSpec struct {
 	Suspend bool `json:"suspend,omitempty"`
 }
 
+// AlertProviderReference references a Provider used by an Alert, along with
+// overrides for the notifications dispatched to this Provider.
+type AlertProviderReference struct {
+	// Name of the Provider.
+	// +required
+	Name string `json:"name"`
+
+	// EventMetadata is merged on top of the Alert .spec.eventMetadata for the
+	// notifications dispatched to this Provider, with the keys specified here
+	// taking precedence.
+	// +optional
+	EventMetadata map[string]string `json:"eventMetadata,omitempty"`
+
+	// Summary overrides the summary of the notifications dispatched to this
+	// Provider, i.e. the Alert .spec.summary and the "summary" key of the
+	// Alert .spec.eventMetadata.
+	// +kubebuilder:validation:MaxLength:=255
+	// +optional
+	Summary string `json:"summary,omitempty"`
+}
+
 // +genclient
 // +kubebuilder:storageversion
 // +kubebuilder:object:root=true
@@ -102,3 +134,24 @@ type AlertList struct {
 func init() {
 	SchemeBuilder.Register(&Alert{}, &AlertList{})
 }
+
+// GetProviderRefs returns the Provider references of this Alert, with the
+// ProviderRef first followed by the ProviderRefs. The ProviderRef is omitted
+// if a Provider with the same name is listed in the ProviderRefs.
+func (in *Alert) GetProviderRefs() []AlertProviderReference {
+	refs := make([]AlertProviderReference, 0, len(in.Spec.ProviderRefs)+1)
+	if in.Spec.ProviderRef != nil && in.Spec.ProviderRef.Name != "" {
+		name := in.Spec.ProviderRef.Name
+		listed := false
+		for _, ref := range in.Spec.ProviderRefs {
+			if ref.Name == name {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			refs = append(refs, AlertProviderReference{Name: name})
+		}
+	}
+	return append(refs, in.Spec.ProviderRefs...)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	"github.com/fluxcd/pkg/apis/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/fluxcd/notification-controller/api/v1"
+)
+
+const (
+	ClusterAlertKind string = "ClusterAlert"
+)
+
+// ClusterAlertSpec defines an alerting rule for events involving a list of
+// objects across namespaces.
+// +kubebuilder:validation:XValidation:rule="(has(self.providerRef) && size(self.providerRef.name) > 0) || (has(self.providerRefs) && size(self.providerRefs) > 0)", message="at least one of spec.providerRef or spec.providerRefs must be specified"
+type ClusterAlertSpec struct {
+	// ProviderRef specifies which ClusterProvider this ClusterAlert should use.
+	// Either ProviderRef or ProviderRefs must be specified.
+	// +optional
+	ProviderRef *meta.LocalObjectReference `json:"providerRef,omitempty"`
+
+	// ProviderRefs specifies a list of ClusterProviders this ClusterAlert
+	// should use, in addition to the ProviderRef, with optional
+	// per-ClusterProvider overrides.
+	// +kubebuilder:validation:MaxItems:=32
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	ProviderRefs []AlertProviderReference `json:"providerRefs,omitempty"`
+
+	// NamespaceSelector selects the namespaces of the involved objects
+	// this ClusterAlert applies to. When not specified, the ClusterAlert
+	// applies to all namespaces.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// EventSeverity specifies how to filter events based on severity.
+	// If set to 'info' no events will be filtered.
+	// +kubebuilder:validation:Enum=info;error
+	// +kubebuilder:default:=info
+	// +optional
+	EventSeverity string `json:"eventSeverity,omitempty"`
+
+	// EventSources specifies how to filter events based
+	// on the involved object kind, name and namespace.
+	// When the namespace of an event source is not specified,
+	// the event source matches the objects in all the namespaces
+	// selected by the NamespaceSelector.
+	// +required
+	EventSources []v1.CrossNamespaceObjectReference `json:"eventSources"`
+
+	// InclusionList specifies a list of Golang regular expressions
+	// to be used for including messages.
+	// +optional
+	InclusionList []string `json:"inclusionList,omitempty"`
+
+	// EventMetadata is an optional field for adding metadata to events dispatched by the
+	// controller. This can be used for enhancing the context of the event.
+	// +optional
+	EventMetadata map[string]string `json:"eventMetadata,omitempty"`
+
+	// ExclusionList specifies a list of Golang regular expressions
+	// to be used for excluding messages.
+	// +optional
+	ExclusionList []string `json:"exclusionList,omitempty"`
+
+	// Suspend tells the controller to suspend subsequent
+	// events handling for this ClusterAlert.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
+
+// ClusterAlert is the Schema for the clusteralerts API
+type ClusterAlert struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterAlertSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterAlertList contains a list of ClusterAlert
+type ClusterAlertList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterAlert `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterAlert{}, &ClusterAlertList{})
+}
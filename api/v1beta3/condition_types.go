@@ -0,0 +1,27 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+const (
+	// TypeNotAllowedCondition indicates that the type of a Provider is not
+	// allowed in its namespace by the Provider type policy of the controller.
+	TypeNotAllowedCondition string = "TypeNotAllowed"
+
+	// TypeNotAllowedReason represents the fact that the type of a Provider
+	// is not allowed in its namespace.
+	TypeNotAllowedReason string = "ProviderTypeNotAllowed"
+)
@@ -52,13 +52,25 @@ const (
 	PagerDutyProvider       string = "pagerduty"
 	DataDogProvider         string = "datadog"
 	NATSProvider            string = "nats"
+	KafkaProvider           string = "kafka"
+	AMQPProvider            string = "amqp"
+	MQTTProvider            string = "mqtt"
+	AWSSNSProvider          string = "awssns"
+	AWSSQSProvider          string = "awssqs"
+	AWSEventBridgeProvider  string = "awseventbridge"
+	SMTPProvider            string = "smtp"
+	MattermostProvider      string = "mattermost"
+	NtfyProvider            string = "ntfy"
+	GotifyProvider          string = "gotify"
+	SplunkHECProvider       string = "splunkhec"
+	LokiProvider            string = "loki"
 )
 
 // ProviderSpec defines the desired state of the Provider.
 // +kubebuilder:validation:XValidation:rule="self.type == 'github' || self.type == 'gitlab' || self.type == 'gitea' || self.type == 'bitbucketserver' || self.type == 'bitbucket' || self.type == 'azuredevops' || !has(self.commitStatusExpr)", message="spec.commitStatusExpr is only supported for the 'github', 'gitlab', 'gitea', 'bitbucketserver', 'bitbucket', 'azuredevops' provider types"
 type ProviderSpec struct {
 	// Type specifies which Provider implementation to use.
-	// +kubebuilder:validation:Enum=slack;discord;msteams;rocket;generic;generic-hmac;github;gitlab;gitea;bitbucketserver;bitbucket;azuredevops;googlechat;googlepubsub;webex;sentry;azureeventhub;telegram;lark;matrix;opsgenie;alertmanager;grafana;githubdispatch;pagerduty;datadog;nats
+	// +kubebuilder:validation:Enum=slack;discord;msteams;rocket;generic;generic-hmac;github;gitlab;gitea;bitbucketserver;bitbucket;azuredevops;googlechat;googlepubsub;webex;sentry;azureeventhub;telegram;lark;matrix;opsgenie;alertmanager;grafana;githubdispatch;pagerduty;datadog;nats;kafka;amqp;mqtt;awssns;awssqs;awseventbridge;smtp;mattermost;ntfy;gotify;splunkhec;loki
 	// +required
 	Type string `json:"type"`
 
@@ -109,7 +121,9 @@ type ProviderSpec struct {
 	SecretRef *meta.LocalObjectReference `json:"secretRef,omitempty"`
 
 	// CertSecretRef specifies the Secret containing
-	// a PEM-encoded CA certificate (in the `ca.crt` key).
+	// a PEM-encoded CA certificate (in the `ca.crt` key), and for the
+	// kafka, amqp and mqtt Provider types, optionally a PEM-encoded
+	// client certificate and private key (in the `tls.crt` and `tls.key` keys).
 	// +optional
 	//
 	// Note: Support for the `caFile` key has
@@ -128,11 +142,29 @@ type ProviderSpec struct {
 	// and alert.
 	// +optional
 	CommitStatusExpr string `json:"commitStatusExpr,omitempty"`
+
+	// FallbackProviderRefs specifies an ordered list of Providers, in the
+	// same namespace, to which notifications are sent when sending them to
+	// this Provider fails. Each fallback Provider is tried in turn until
+	// one succeeds. The fallbacks of the fallback Providers are ignored.
+	// +kubebuilder:validation:MaxItems:=8
+	// +optional
+	FallbackProviderRefs []meta.LocalObjectReference `json:"fallbackProviderRefs,omitempty"`
+}
+
+// ProviderStatus defines the observed state of the Provider.
+type ProviderStatus struct {
+	// Conditions holds the conditions for the Provider, e.g. the
+	// TypeNotAllowed condition when its type is not allowed in its
+	// namespace by the Provider type policy of the controller.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +genclient
 // +kubebuilder:storageversion
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description=""
 
 // Provider is the Schema for the providers API
@@ -141,6 +173,19 @@ type Provider struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec ProviderSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status ProviderStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the status conditions of the object.
+func (in *Provider) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the status conditions on the object.
+func (in *Provider) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
 }
 
 //+kubebuilder:object:root=true
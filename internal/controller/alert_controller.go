@@ -28,6 +28,7 @@ import (
 
 	apiv1 "github.com/fluxcd/notification-controller/api/v1"
 	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/index"
 	"github.com/fluxcd/pkg/runtime/patch"
 )
 
@@ -43,6 +44,16 @@ type AlertReconciler struct {
 }
 
 func (r *AlertReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// These indexes are used to list the Alerts and ClusterAlerts by their
+	// event sources after the event server gets an event.
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1beta3.Alert{},
+		index.AlertEventSourceKey, index.AlertEventSources); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &apiv1beta3.ClusterAlert{},
+		index.ClusterAlertEventSourceKey, index.ClusterAlertEventSources); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1beta3.Alert{}, builder.WithPredicates(finalizerPredicate{})).
 		Complete(r)
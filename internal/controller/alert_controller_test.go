@@ -57,7 +57,7 @@ func TestAlertReconciler(t *testing.T) {
 
 	alert.ObjectMeta.Finalizers = append(alert.ObjectMeta.Finalizers, "foo.bar", apiv1.NotificationFinalizer)
 	alert.Spec = apiv1beta3.AlertSpec{
-		ProviderRef:  meta.LocalObjectReference{Name: "foo-provider"},
+		ProviderRef:  &meta.LocalObjectReference{Name: "foo-provider"},
 		EventSources: []apiv1.CrossNamespaceObjectReference{},
 	}
 	g.Expect(testEnv.Create(ctx, alert)).ToNot(HaveOccurred())
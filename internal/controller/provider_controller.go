@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"errors"
 
 	corev1 "k8s.io/api/core/v1"
 	kuberecorder "k8s.io/client-go/tools/record"
@@ -25,29 +26,65 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/fluxcd/notification-controller/api/v1"
 	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/policy"
+	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
 )
 
 // +kubebuilder:rbac:groups=notification.toolkit.fluxcd.io,resources=providers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=notification.toolkit.fluxcd.io,resources=providers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // ProviderReconciler reconciles a Provider object to migrate it to static
-// Provider.
+// Provider, and to report in its status if its type is not allowed by the
+// ProviderTypePolicy.
 type ProviderReconciler struct {
 	client.Client
 	kuberecorder.EventRecorder
 
-	ControllerName string
+	ControllerName     string
+	ProviderTypePolicy *policy.ProviderTypePolicy
 }
 
 func (r *ProviderReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&apiv1beta3.Provider{}, builder.WithPredicates(finalizerPredicate{})).
-		Complete(r)
+	// The Providers are reconciled on changes even without a policy, to
+	// remove the conditions set with a previous policy.
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1beta3.Provider{}, builder.WithPredicates(
+			predicate.Or(finalizerPredicate{}, predicate.GenerationChangedPredicate{}),
+		))
+
+	// The policy can match the namespaces by their labels, so the Providers
+	// are reconciled again when the labels of their namespace change.
+	if r.ProviderTypePolicy != nil {
+		b = b.Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{}),
+		)
+	}
+	return b.Complete(r)
+}
+
+// requestsForNamespace returns the reconcile requests of the Providers in the
+// given namespace.
+func (r *ProviderReconciler) requestsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list apiv1beta3.ProviderList
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetName())); err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to list Providers", "namespace", obj.GetName())
+		return nil
+	}
+	reqs := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return reqs
 }
 
 func (r *ProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, retErr error) {
@@ -58,6 +95,11 @@ func (r *ProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Report the Providers of types not allowed in their namespace.
+	if err := r.reconcileTypePolicy(ctx, obj); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Early return if no migration is needed.
 	if !controllerutil.ContainsFinalizer(obj, apiv1.NotificationFinalizer) {
 		return ctrl.Result{}, nil
@@ -94,3 +136,33 @@ func (r *ProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 
 	return
 }
+
+// reconcileTypePolicy sets the TypeNotAllowed condition of the given Provider
+// if its type is not allowed in its namespace by the ProviderTypePolicy, and
+// removes it otherwise.
+func (r *ProviderReconciler) reconcileTypePolicy(ctx context.Context, obj *apiv1beta3.Provider) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	validationErr := r.ProviderTypePolicy.Validate(ctx, r.Client, obj)
+	if validationErr != nil && !errors.Is(validationErr, policy.ErrProviderTypeNotAllowed) {
+		return validationErr
+	}
+	if validationErr == nil && !conditions.Has(obj, apiv1beta3.TypeNotAllowedCondition) {
+		return nil
+	}
+
+	patcher, err := patch.NewHelper(obj, r.Client)
+	if err != nil {
+		return err
+	}
+	if validationErr != nil {
+		log.Error(validationErr, "provider type not allowed")
+		r.Event(obj, corev1.EventTypeWarning, apiv1beta3.TypeNotAllowedReason, validationErr.Error())
+		conditions.MarkTrue(obj, apiv1beta3.TypeNotAllowedCondition, apiv1beta3.TypeNotAllowedReason, "%s", validationErr)
+	} else {
+		conditions.Delete(obj, apiv1beta3.TypeNotAllowedCondition)
+	}
+	return patcher.Patch(ctx, obj, patch.WithOwnedConditions{
+		Conditions: []string{apiv1beta3.TypeNotAllowedCondition},
+	})
+}
@@ -18,18 +18,28 @@ package controller
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/fluxcd/pkg/runtime/conditions"
 	"github.com/fluxcd/pkg/runtime/patch"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	apiv1 "github.com/fluxcd/notification-controller/api/v1"
 	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/policy"
 )
 
 func TestProviderReconciler(t *testing.T) {
@@ -199,3 +209,108 @@ func TestProviderReconciler_APIServerValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderReconciler_reconcileTypePolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	g.Expect(os.WriteFile(path, []byte("rules:\n- namespaces: [team-a]\n  allowedTypes: [slack]\n"), 0o600)).To(Succeed())
+	typePolicy, err := policy.LoadProviderTypePolicy(path)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	provider := &apiv1beta3.Provider{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "dispatch",
+			Namespace:  "team-a",
+			Generation: 1,
+		},
+		Spec: apiv1beta3.ProviderSpec{Type: apiv1beta3.GitHubDispatchProvider},
+	}
+	kubeClient := fakeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(provider).
+		WithStatusSubresource(&apiv1beta3.Provider{}).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	r := &ProviderReconciler{
+		Client:             kubeClient,
+		EventRecorder:      recorder,
+		ProviderTypePolicy: typePolicy,
+	}
+	providerKey := client.ObjectKeyFromObject(provider)
+
+	// The condition is set when the type is not allowed.
+	obj := &apiv1beta3.Provider{}
+	g.Expect(kubeClient.Get(ctx, providerKey, obj)).To(Succeed())
+	g.Expect(r.reconcileTypePolicy(ctx, obj)).To(Succeed())
+	g.Expect(kubeClient.Get(ctx, providerKey, obj)).To(Succeed())
+	g.Expect(conditions.IsTrue(obj, apiv1beta3.TypeNotAllowedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(obj, apiv1beta3.TypeNotAllowedCondition)).To(Equal(apiv1beta3.TypeNotAllowedReason))
+	g.Expect(conditions.GetMessage(obj, apiv1beta3.TypeNotAllowedCondition)).
+		To(ContainSubstring("type 'githubdispatch' is not allowed in namespace 'team-a'"))
+	g.Expect(recorder.Events).To(Receive(ContainSubstring(apiv1beta3.TypeNotAllowedReason)))
+
+	// The condition is removed once the type is allowed.
+	obj.Spec.Type = apiv1beta3.SlackProvider
+	g.Expect(kubeClient.Update(ctx, obj)).To(Succeed())
+	g.Expect(r.reconcileTypePolicy(ctx, obj)).To(Succeed())
+	g.Expect(kubeClient.Get(ctx, providerKey, obj)).To(Succeed())
+	g.Expect(conditions.Has(obj, apiv1beta3.TypeNotAllowedCondition)).To(BeFalse())
+
+	// The condition set with a previous policy is removed without policy.
+	conditions.MarkTrue(obj, apiv1beta3.TypeNotAllowedCondition, apiv1beta3.TypeNotAllowedReason, "not allowed")
+	g.Expect(kubeClient.Status().Update(ctx, obj)).To(Succeed())
+	r.ProviderTypePolicy = nil
+	g.Expect(r.reconcileTypePolicy(ctx, obj)).To(Succeed())
+	g.Expect(kubeClient.Get(ctx, providerKey, obj)).To(Succeed())
+	g.Expect(conditions.Has(obj, apiv1beta3.TypeNotAllowedCondition)).To(BeFalse())
+}
+
+func TestProviderReconciler_requestsForNamespace(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	g.Expect(os.WriteFile(path, []byte("rules:\n- namespaceSelector:\n    matchLabels:\n      tenant: \"true\"\n  allowedTypes: [slack]\n"), 0o600)).To(Succeed())
+	typePolicy, err := policy.LoadProviderTypePolicy(path)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}
+	newProvider := func(namespace, name string) *apiv1beta3.Provider {
+		return &apiv1beta3.Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       apiv1beta3.ProviderSpec{Type: apiv1beta3.GitHubDispatchProvider},
+		}
+	}
+	kubeClient := fakeclient.NewClientBuilder().
+		WithScheme(scheme.Scheme).
+		WithObjects(ns, newProvider("team-a", "dispatch"), newProvider("team-a", "generic"), newProvider("team-b", "dispatch")).
+		WithStatusSubresource(&apiv1beta3.Provider{}).
+		Build()
+	r := &ProviderReconciler{
+		Client:             kubeClient,
+		EventRecorder:      record.NewFakeRecorder(10),
+		ProviderTypePolicy: typePolicy,
+	}
+
+	// The Providers of the namespace are reconciled when its labels change.
+	g.Expect(r.requestsForNamespace(ctx, ns)).To(ConsistOf(
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "dispatch"}},
+		reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "generic"}},
+	))
+
+	providerKey := types.NamespacedName{Namespace: "team-a", Name: "dispatch"}
+	_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: providerKey})
+	g.Expect(err).ToNot(HaveOccurred())
+	obj := &apiv1beta3.Provider{}
+	g.Expect(kubeClient.Get(ctx, providerKey, obj)).To(Succeed())
+	g.Expect(conditions.Has(obj, apiv1beta3.TypeNotAllowedCondition)).To(BeFalse())
+
+	ns.Labels = map[string]string{"tenant": "true"}
+	g.Expect(kubeClient.Update(ctx, ns)).To(Succeed())
+	for _, req := range r.requestsForNamespace(ctx, ns) {
+		_, err = r.Reconcile(ctx, req)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(kubeClient.Get(ctx, providerKey, obj)).To(Succeed())
+	g.Expect(conditions.IsTrue(obj, apiv1beta3.TypeNotAllowedCondition)).To(BeTrue())
+}
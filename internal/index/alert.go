@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package index holds the field indexes of the notification resources,
+// registered by the reconcilers and queried by the event server.
+package index
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+const (
+	// AlertEventSourceKey is the index of the Alerts by the namespace
+	// and kind of their event sources.
+	AlertEventSourceKey string = ".spec.eventSources.namespaceKind"
+
+	// ClusterAlertEventSourceKey is the index of the ClusterAlerts by
+	// the kind of their event sources.
+	ClusterAlertEventSourceKey string = ".spec.eventSources.kind"
+)
+
+// AlertEventSources returns the namespace and kind of the event sources
+// of the given Alert, in the form used by AlertEventSourceKey.
+func AlertEventSources(o client.Object) []string {
+	alert := o.(*apiv1beta3.Alert)
+	var keys []string
+	for _, source := range alert.Spec.EventSources {
+		namespace := source.Namespace
+		if namespace == "" {
+			namespace = alert.Namespace
+		}
+		keys = append(keys, AlertEventSourceValue(namespace, source.Kind))
+	}
+	return keys
+}
+
+// ClusterAlertEventSources returns the kind of the event sources of the
+// given ClusterAlert, in the form used by ClusterAlertEventSourceKey.
+func ClusterAlertEventSources(o client.Object) []string {
+	clusterAlert := o.(*apiv1beta3.ClusterAlert)
+	var keys []string
+	for _, source := range clusterAlert.Spec.EventSources {
+		keys = append(keys, source.Kind)
+	}
+	return keys
+}
+
+// AlertEventSourceValue returns the AlertEventSourceKey value matching the
+// event sources of the given namespace and kind.
+func AlertEventSourceValue(namespace, kind string) string {
+	return namespace + "/" + kind
+}
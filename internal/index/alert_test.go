@@ -0,0 +1,48 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package index
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1"
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestAlertEventSources(t *testing.T) {
+	g := NewWithT(t)
+
+	alert := &apiv1beta3.Alert{}
+	alert.Namespace = "foo-ns"
+	alert.Spec.EventSources = []apiv1.CrossNamespaceObjectReference{
+		{Kind: "Kustomization", Name: "*"},
+		{Kind: "GitRepository", Name: "app", Namespace: "bar-ns"},
+	}
+	g.Expect(AlertEventSources(alert)).To(Equal([]string{
+		"foo-ns/Kustomization",
+		"bar-ns/GitRepository",
+	}))
+
+	clusterAlert := &apiv1beta3.ClusterAlert{}
+	clusterAlert.Spec.EventSources = alert.Spec.EventSources
+	g.Expect(ClusterAlertEventSources(clusterAlert)).To(Equal([]string{
+		"Kustomization",
+		"GitRepository",
+	}))
+}
@@ -32,6 +32,8 @@ import (
 )
 
 type Alertmanager struct {
+	egress
+
 	URL      string
 	ProxyURL string
 	CertPool *x509.CertPool
@@ -94,19 +96,47 @@ func (s *Alertmanager) Post(ctx context.Context, event eventv1.Event) error {
 		return nil
 	}
 
+	payload := []AlertManagerAlert{newAlertManagerAlert(event)}
+
+	// Resolve the alert fired for the last failure of the involved object.
+	// Alertmanager identifies alerts by their label set, hence the resolved
+	// alert is built from the failed event.
+	if r, ok := ResolutionFromContext(ctx); ok {
+		resolved := newAlertManagerAlert(r.FailedEvent)
+		resolved.Status = "resolved"
+		resolved.StartsAt = AlertManagerTime(r.FailingSince)
+		resolved.EndsAt = AlertManagerTime(event.Timestamp.Time)
+		payload = append(payload, resolved)
+	}
+
+	var opts []requestOptFunc
+	if s.Token != "" {
+		opts = append(opts, func(request *retryablehttp.Request) {
+			request.Header.Add("Authorization", "Bearer "+s.Token)
+		})
+	}
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload, opts...)
+	if err != nil {
+		return fmt.Errorf("postMessage failed: %w", err)
+	}
+	return nil
+}
+
+// newAlertManagerAlert returns a firing Alertmanager alert for the given event.
+func newAlertManagerAlert(event eventv1.Event) AlertManagerAlert {
 	annotations := make(map[string]string)
 	annotations["message"] = event.Message
 
-	_, ok := event.Metadata["summary"]
-	if ok {
-		annotations["summary"] = event.Metadata["summary"]
-		delete(event.Metadata, "summary")
+	var labels = make(map[string]string)
+	for k, v := range event.Metadata {
+		labels[k] = v
 	}
 
-	var labels = make(map[string]string)
-	if event.Metadata != nil {
-		labels = event.Metadata
+	if summary, ok := labels["summary"]; ok {
+		annotations["summary"] = summary
+		delete(labels, "summary")
 	}
+
 	labels["alertname"] = "Flux" + event.InvolvedObject.Kind + cases.Title(language.Und).String(event.Reason)
 	labels["severity"] = event.Severity
 	labels["reason"] = event.Reason
@@ -122,30 +152,15 @@ func (s *Alertmanager) Post(ctx context.Context, event eventv1.Event) error {
 	// the alert is cleared after the timeout). Due to
 	// event.InvolvedObject only containing the object reference (namely
 	// the GVKNN) best we can do is leave it unset up to Alertmanager's
-	// default `resolve_timeout`.
+	// default `resolve_timeout`, unless the alert gets explicitly resolved
+	// when the involved object recovers.
 	//
 	// https://prometheus.io/docs/alerting/0.27/configuration/#file-layout-and-global-settings
-	startsAt := AlertManagerTime(event.Timestamp.Time)
-
-	payload := []AlertManagerAlert{
-		{
-			Labels:      labels,
-			Annotations: annotations,
-			Status:      "firing",
+	return AlertManagerAlert{
+		Labels:      labels,
+		Annotations: annotations,
+		Status:      "firing",
 
-			StartsAt: startsAt,
-		},
+		StartsAt: AlertManagerTime(event.Timestamp.Time),
 	}
-
-	var opts []requestOptFunc
-	if s.Token != "" {
-		opts = append(opts, func(request *retryablehttp.Request) {
-			request.Header.Add("Authorization", "Bearer "+s.Token)
-		})
-	}
-	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, payload, opts...)
-	if err != nil {
-		return fmt.Errorf("postMessage failed: %w", err)
-	}
-	return nil
 }
@@ -23,8 +23,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 )
 
 func TestAlertmanager_Post(t *testing.T) {
@@ -44,3 +47,41 @@ func TestAlertmanager_Post(t *testing.T) {
 	err = alertmanager.Post(context.TODO(), testEvent())
 	require.NoError(t, err)
 }
+
+func TestAlertmanager_PostResolution(t *testing.T) {
+	var payload []AlertManagerAlert
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &payload))
+	}))
+	defer ts.Close()
+
+	alertmanager, err := NewAlertmanager(ts.URL, "", nil, "")
+	require.NoError(t, err)
+
+	failed := testEvent()
+	failed.Severity = eventv1.EventSeverityError
+	failed.Reason = "ReconciliationFailed"
+	failed.Metadata["summary"] = "cluster"
+	failingSince := failed.Timestamp.Add(-time.Hour).Truncate(time.Second)
+
+	ctx := WithResolution(context.TODO(), Resolution{
+		FailedEvent:  failed,
+		FailingSince: failingSince,
+	})
+	event := testEvent()
+	require.NoError(t, alertmanager.Post(ctx, event))
+
+	require.Len(t, payload, 2)
+	require.Equal(t, "firing", payload[0].Status)
+	resolved := payload[1]
+	require.Equal(t, "resolved", resolved.Status)
+	require.Equal(t, "FluxGitRepositoryReconciliationfailed", resolved.Labels["alertname"])
+	require.Equal(t, eventv1.EventSeverityError, resolved.Labels["severity"])
+	require.Equal(t, "metadata", resolved.Labels["test"])
+	require.NotContains(t, resolved.Labels, "summary")
+	require.Equal(t, "cluster", resolved.Annotations["summary"])
+	require.True(t, time.Time(resolved.StartsAt).Equal(failingSince))
+	require.True(t, time.Time(resolved.EndsAt).Equal(event.Timestamp.Truncate(time.Second)))
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// amqpDefaultRoutingKey is the routing key template used when none is set
+// in the Provider secret.
+const amqpDefaultRoutingKey = "flux.<namespace>.<kind>.<severity>"
+
+type (
+	// AMQP holds an AMQP client and target exchange.
+	AMQP struct {
+		egress
+
+		exchange   string
+		routingKey string
+		headers    map[string]string
+		client     interface {
+			publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) (err error)
+		}
+	}
+
+	amqpClient struct {
+		egress    *egress
+		url       string
+		username  string
+		password  string
+		tlsConfig *tls.Config
+	}
+)
+
+// NewAMQP creates an AMQP notifier publishing to the given exchange of the
+// broker at the given amqp:// or amqps:// URL. The routing key template is
+// read from the secret data, with the <namespace>, <kind>, <name>,
+// <severity> and <reason> placeholders replaced by the event fields.
+func NewAMQP(url, exchange, username, password string, headers map[string]string,
+	certPool *x509.CertPool, clientCert *tls.Certificate, secretData map[string][]byte) (*AMQP, error) {
+	if url == "" {
+		return nil, errors.New("AMQP server (address) cannot be empty")
+	}
+	uri, err := amqp.ParseURI(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AMQP server address: %w", err)
+	}
+
+	routingKey := amqpDefaultRoutingKey
+	if v, ok := secretData["routingKey"]; ok {
+		routingKey = strings.TrimSpace(string(v))
+	}
+	if routingKey == "" && exchange == "" {
+		return nil, errors.New("AMQP routing key cannot be empty when publishing to the default exchange")
+	}
+
+	client := &amqpClient{
+		url:      url,
+		username: username,
+		password: password,
+	}
+	if uri.Scheme == "amqps" || certPool != nil || clientCert != nil {
+		if uri.Scheme != "amqps" {
+			return nil, errors.New("AMQP server (address) must use the amqps scheme with TLS certificates")
+		}
+		client.tlsConfig = &tls.Config{
+			RootCAs:    certPool,
+			MinVersion: tls.VersionTLS12,
+		}
+		if clientCert != nil {
+			client.tlsConfig.Certificates = []tls.Certificate{*clientCert}
+		}
+	}
+
+	a := &AMQP{
+		exchange:   exchange,
+		routingKey: routingKey,
+		headers:    headers,
+		client:     client,
+	}
+	client.egress = &a.egress
+	return a, nil
+}
+
+// Post publishes Flux events to an AMQP exchange.
+func (a *AMQP) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	eventPayload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error json-marshaling event: %w", err)
+	}
+
+	headers := amqp.Table{}
+	for key, value := range a.headers {
+		headers[key] = value
+	}
+	if event.ReportingController != "" {
+		headers["reportingController"] = event.ReportingController
+	}
+	if event.ReportingInstance != "" {
+		headers["reportingInstance"] = event.ReportingInstance
+	}
+
+	timestamp := event.Timestamp.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	msg := amqp.Publishing{
+		Headers:      headers,
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    timestamp,
+		Type:         event.Reason,
+		AppId:        "notification-controller",
+		Body:         eventPayload,
+	}
+
+	routingKey := a.formatRoutingKey(event)
+	err = a.client.publish(ctx, a.exchange, routingKey, msg)
+	if err != nil {
+		return fmt.Errorf("error publishing event to exchange '%s' with routing key '%s': %w", a.exchange, routingKey, err)
+	}
+
+	// debug log
+	log.FromContext(ctx).V(1).Info("Event published to AMQP exchange", "exchange", a.exchange, "routingKey", routingKey)
+
+	return nil
+}
+
+// formatRoutingKey replaces the placeholders of the routing key template
+// with the event fields.
+func (a *AMQP) formatRoutingKey(event eventv1.Event) string {
+	return strings.NewReplacer(
+		"<namespace>", event.InvolvedObject.Namespace,
+		"<kind>", event.InvolvedObject.Kind,
+		"<name>", event.InvolvedObject.Name,
+		"<severity>", event.Severity,
+		"<reason>", event.Reason,
+	).Replace(a.routingKey)
+}
+
+func (a *amqpClient) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) (err error) {
+	config := amqp.Config{
+		Properties:      amqp.NewConnectionProperties(),
+		TLSClientConfig: a.tlsConfig,
+		Dial: func(network, addr string) (net.Conn, error) {
+			return a.egress.newEgressDialer(nil).DialContext(ctx, network, addr)
+		},
+	}
+	config.Properties.SetClientConnectionName("notification-controller")
+	if a.username != "" && a.password != "" {
+		config.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: a.username, Password: a.password}}
+	}
+
+	conn, err := amqp.DialConfig(a.url, config)
+	if err != nil {
+		return fmt.Errorf("error connecting to server: %w", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("error opening channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.Confirm(false); err != nil {
+		return fmt.Errorf("error enabling publisher confirms: %w", err)
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, msg)
+	if err != nil {
+		return fmt.Errorf("error publishing message to server: %w", err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for publisher confirm: %w", err)
+	}
+	if !acked {
+		return errors.New("message was not acknowledged by the server")
+	}
+
+	return nil
+}
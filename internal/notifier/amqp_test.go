@@ -0,0 +1,208 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	amqp "github.com/rabbitmq/amqp091-go"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestNewAMQP(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		exchange           string
+		certPool           *x509.CertPool
+		secretData         map[string][]byte
+		expectedErr        string
+		expectedRoutingKey string
+		expectedTLS        bool
+	}{
+		{
+			name:        "empty url is not allowed",
+			exchange:    "flux",
+			expectedErr: "AMQP server (address) cannot be empty",
+		},
+		{
+			name:        "invalid scheme is not allowed",
+			url:         "http://rabbitmq:5672",
+			exchange:    "flux",
+			expectedErr: "invalid AMQP server address",
+		},
+		{
+			name:        "certificates require the amqps scheme",
+			url:         "amqp://rabbitmq:5672",
+			exchange:    "flux",
+			certPool:    x509.NewCertPool(),
+			expectedErr: "must use the amqps scheme",
+		},
+		{
+			name:        "empty routing key on default exchange is not allowed",
+			url:         "amqp://rabbitmq:5672",
+			secretData:  map[string][]byte{"routingKey": []byte(" ")},
+			expectedErr: "AMQP routing key cannot be empty",
+		},
+		{
+			name:               "default routing key",
+			url:                "amqp://rabbitmq:5672/vhost",
+			exchange:           "flux",
+			expectedRoutingKey: amqpDefaultRoutingKey,
+		},
+		{
+			name:               "custom routing key with TLS",
+			url:                "amqps://rabbitmq:5671",
+			certPool:           x509.NewCertPool(),
+			secretData:         map[string][]byte{"routingKey": []byte("events.<name>")},
+			expectedRoutingKey: "events.<name>",
+			expectedTLS:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewAMQP(tt.url, tt.exchange, "user", "pass", nil, tt.certPool, nil, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				g.Expect(provider).To(BeNil())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(provider.exchange).To(Equal(tt.exchange))
+			g.Expect(provider.routingKey).To(Equal(tt.expectedRoutingKey))
+
+			client := provider.client.(*amqpClient)
+			g.Expect(client.url).To(Equal(tt.url))
+			g.Expect(client.username).To(Equal("user"))
+			g.Expect(client.password).To(Equal("pass"))
+			g.Expect(client.tlsConfig != nil).To(Equal(tt.expectedTLS))
+		})
+	}
+}
+
+type amqpPostTestCase struct {
+	name                 string
+	routingKey           string
+	headers              map[string]string
+	event                eventv1.Event
+	expectedRoutingKey   string
+	expectedHeaders      amqp.Table
+	publishErr           error
+	expectedErr          error
+	publishShouldExecute bool
+	publishExecuted      bool
+
+	g *WithT
+}
+
+func (tt *amqpPostTestCase) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) (err error) {
+	tt.g.THelper()
+	tt.publishExecuted = true
+	tt.g.Expect(exchange).To(Equal("flux"))
+	tt.g.Expect(routingKey).To(Equal(tt.expectedRoutingKey))
+	tt.g.Expect(msg.ContentType).To(Equal("application/json"))
+	tt.g.Expect(msg.DeliveryMode).To(Equal(amqp.Persistent))
+	tt.g.Expect(msg.Headers).To(Equal(tt.expectedHeaders))
+	tt.g.Expect(string(msg.Body)).To(ContainSubstring(`"message":"` + tt.event.Message + `"`))
+	return tt.publishErr
+}
+
+func TestAMQPPost(t *testing.T) {
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Kustomization",
+			Namespace: "flux-system",
+			Name:      "apps",
+		},
+		Severity:            eventv1.EventSeverityError,
+		Reason:              "ReconciliationFailed",
+		Message:             "apply failed",
+		ReportingController: "kustomize-controller",
+	}
+
+	tests := []*amqpPostTestCase{
+		{
+			name:               "default routing key and headers",
+			routingKey:         amqpDefaultRoutingKey,
+			headers:            map[string]string{"env": "prod"},
+			event:              event,
+			expectedRoutingKey: "flux.flux-system.Kustomization.error",
+			expectedHeaders: amqp.Table{
+				"env":                 "prod",
+				"reportingController": "kustomize-controller",
+			},
+			publishShouldExecute: true,
+		},
+		{
+			name:                 "custom routing key",
+			routingKey:           "<reason>.<name>",
+			event:                event,
+			expectedRoutingKey:   "ReconciliationFailed.apps",
+			expectedHeaders:      amqp.Table{"reportingController": "kustomize-controller"},
+			publishShouldExecute: true,
+		},
+		{
+			name: "commit status updates are dropped",
+			event: eventv1.Event{
+				Metadata: map[string]string{"commit_status": "update"},
+			},
+			publishShouldExecute: false,
+		},
+		{
+			name:                 "publish error is wrapped and relayed",
+			routingKey:           "events",
+			event:                eventv1.Event{Message: "test"},
+			expectedRoutingKey:   "events",
+			expectedHeaders:      amqp.Table{},
+			publishErr:           errors.New("publish error"),
+			expectedErr:          fmt.Errorf("error publishing event to exchange 'flux' with routing key 'events': %w", errors.New("publish error")),
+			publishShouldExecute: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tt.g = g
+
+			provider := &AMQP{
+				exchange:   "flux",
+				routingKey: tt.routingKey,
+				headers:    tt.headers,
+				client:     tt,
+			}
+
+			err := provider.Post(context.Background(), tt.event)
+			if tt.expectedErr == nil {
+				g.Expect(err).To(BeNil())
+			} else {
+				g.Expect(err).To(Equal(tt.expectedErr))
+			}
+			g.Expect(tt.publishExecuted).To(Equal(tt.publishShouldExecute))
+		})
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+// awsOptions holds the region, endpoint, credentials and connection options
+// of the AWS clients.
+type awsOptions struct {
+	region          string
+	endpoint        string
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	proxyURL        *url.URL
+	certPool        *x509.CertPool
+}
+
+// newAWSOptions returns the options of the AWS clients in the given region,
+// overridden by the region, endpoint and static credentials of the secret
+// data. Without region, the region of the environment is used, e.g. the
+// AWS_REGION variable. Without static credentials, the default credential
+// chain is used, e.g. the IRSA web identity token of the controller service
+// account.
+func newAWSOptions(region, proxyURL string, certPool *x509.CertPool, secretData map[string][]byte) (*awsOptions, error) {
+	o := &awsOptions{
+		region:   region,
+		certPool: certPool,
+	}
+	if v, ok := secretData["region"]; ok {
+		o.region = strings.TrimSpace(string(v))
+	}
+	if v, ok := secretData["endpoint"]; ok {
+		o.endpoint = strings.TrimSpace(string(v))
+		if _, err := url.ParseRequestURI(o.endpoint); err != nil {
+			return nil, fmt.Errorf("invalid AWS endpoint: %w", err)
+		}
+	}
+
+	o.accessKeyID = strings.TrimSpace(string(secretData["accessKeyID"]))
+	o.secretAccessKey = strings.TrimSpace(string(secretData["secretAccessKey"]))
+	o.sessionToken = strings.TrimSpace(string(secretData["sessionToken"]))
+	if (o.accessKeyID == "") != (o.secretAccessKey == "") {
+		return nil, errors.New("AWS static credentials must have both the accessKeyID and secretAccessKey")
+	}
+
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL %q: %w", proxyURL, err)
+		}
+		o.proxyURL = proxy
+	}
+
+	return o, nil
+}
+
+// loadConfig returns the AWS config of the clients, with an HTTP client
+// connecting through the proxy and to the addresses allowed by the policy of
+// the given egress. The config caches the credentials, and must be reused
+// across the requests of a notifier.
+func (o *awsOptions) loadConfig(ctx context.Context, e *egress) (aws.Config, error) {
+	httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
+		if o.proxyURL != nil {
+			tr.Proxy = http.ProxyURL(o.proxyURL)
+		}
+		e.enforceTransport(tr)
+		if o.certPool != nil {
+			if tr.TLSClientConfig == nil {
+				tr.TLSClientConfig = &tls.Config{}
+			}
+			tr.TLSClientConfig.RootCAs = o.certPool
+		}
+	})
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithHTTPClient(httpClient),
+	}
+	if o.region != "" {
+		opts = append(opts, config.WithRegion(o.region))
+	}
+	if o.accessKeyID != "" {
+		opts = append(opts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(o.accessKeyID, o.secretAccessKey, o.sessionToken)))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if cfg.Region == "" {
+		return aws.Config{}, errors.New("AWS region cannot be empty")
+	}
+	if o.endpoint != "" {
+		cfg.BaseEndpoint = aws.String(o.endpoint)
+	}
+	return cfg, nil
+}
+
+// awsMessageAttributes returns the message attributes of the given event,
+// with the given headers.
+func awsMessageAttributes(event eventv1.Event, headers map[string]string) map[string]string {
+	attrs := make(map[string]string, len(headers)+4)
+	for key, value := range headers {
+		attrs[key] = value
+	}
+	for key, value := range map[string]string{
+		"severity":  event.Severity,
+		"kind":      event.InvolvedObject.Kind,
+		"namespace": event.InvolvedObject.Namespace,
+		"name":      event.InvolvedObject.Name,
+	} {
+		// Empty attribute values are rejected by AWS.
+		if value != "" {
+			attrs[key] = value
+		}
+	}
+	return attrs
+}
+
+// awsMessageGroupID returns the FIFO message group of the given event, so that
+// the events of an object are kept in order.
+func awsMessageGroupID(event eventv1.Event) string {
+	obj := event.InvolvedObject
+	return fmt.Sprintf("%s/%s/%s", obj.Kind, obj.Namespace, obj.Name)
+}
+
+// awsMessageDeduplicationID returns the FIFO deduplication ID of the given
+// event payload.
+func awsMessageDeduplicationID(eventPayload []byte) string {
+	sum := sha256.Sum256(eventPayload)
+	return hex.EncodeToString(sum[:])
+}
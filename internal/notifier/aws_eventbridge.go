@@ -0,0 +1,162 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	eventbridgetypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// awsEventBridgeDefaultDetailType is the detail type of the events without
+// reason.
+const awsEventBridgeDefaultDetailType = "Flux Event"
+
+type (
+	// AWSEventBridge holds an AWS EventBridge client and target event bus.
+	AWSEventBridge struct {
+		egress
+
+		eventBus string
+		source   string
+		client   interface {
+			putEvent(ctx context.Context, entry eventbridgetypes.PutEventsRequestEntry) (eventID string, err error)
+		}
+	}
+
+	awsEventBridgeClient struct {
+		client *eventbridge.Client
+	}
+)
+
+// ensure *AWSEventBridge implements Interface.
+var _ Interface = &AWSEventBridge{}
+
+// NewAWSEventBridge creates an AWS EventBridge notifier putting events onto
+// the event bus of the given name or ARN. The source of the events is the
+// given source, or fluxcd.<reporting controller> if empty.
+func NewAWSEventBridge(eventBus, source, proxyURL string,
+	certPool *x509.CertPool, secretData map[string][]byte) (*AWSEventBridge, error) {
+	if eventBus == "" {
+		return nil, errors.New("AWS EventBridge event bus (address) cannot be empty")
+	}
+	if strings.HasPrefix(source, "aws.") {
+		return nil, fmt.Errorf("AWS EventBridge source (channel) '%s' cannot start with 'aws.'", source)
+	}
+
+	var region string
+	if arn.IsARN(eventBus) {
+		bus, err := arn.Parse(eventBus)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AWS EventBridge event bus ARN '%s': %w", eventBus, err)
+		}
+		if bus.Service != "events" || !strings.HasPrefix(bus.Resource, "event-bus/") {
+			return nil, fmt.Errorf("invalid AWS EventBridge event bus ARN '%s': resource must be an events event-bus", eventBus)
+		}
+		region = bus.Region
+	}
+
+	opts, err := newAWSOptions(region, proxyURL, certPool, secretData)
+	if err != nil {
+		return nil, err
+	}
+	e := &AWSEventBridge{
+		eventBus: eventBus,
+		source:   source,
+	}
+	cfg, err := opts.loadConfig(context.Background(), &e.egress)
+	if err != nil {
+		return nil, err
+	}
+	e.client = &awsEventBridgeClient{client: eventbridge.NewFromConfig(cfg)}
+	return e, nil
+}
+
+// Post puts Flux events onto an AWS EventBridge event bus.
+func (e *AWSEventBridge) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	eventPayload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error json-marshaling event: %w", err)
+	}
+
+	source := e.source
+	if source == "" {
+		source = "fluxcd." + event.ReportingController
+		if event.ReportingController == "" {
+			source = "fluxcd"
+		}
+	}
+	detailType := event.Reason
+	if detailType == "" {
+		detailType = awsEventBridgeDefaultDetailType
+	}
+	entry := eventbridgetypes.PutEventsRequestEntry{
+		EventBusName: aws.String(e.eventBus),
+		Source:       aws.String(source),
+		DetailType:   aws.String(detailType),
+		Detail:       aws.String(string(eventPayload)),
+	}
+	if !event.Timestamp.IsZero() {
+		entry.Time = aws.Time(event.Timestamp.Time)
+	}
+
+	eventID, err := e.client.putEvent(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("error putting event onto event bus %s: %w", e.eventBus, err)
+	}
+
+	// debug log
+	log.FromContext(ctx).V(1).Info("Event put onto AWS EventBridge event bus",
+		"eventBus", e.eventBus,
+		"event id", eventID)
+
+	return nil
+}
+
+// putEvent puts the given entry onto its event bus, returning an error if
+// the entry was rejected.
+func (e *awsEventBridgeClient) putEvent(ctx context.Context, entry eventbridgetypes.PutEventsRequestEntry) (eventID string, err error) {
+	output, err := e.client.PutEvents(ctx, &eventbridge.PutEventsInput{
+		Entries: []eventbridgetypes.PutEventsRequestEntry{entry},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(output.Entries) != 1 {
+		return "", fmt.Errorf("unexpected number of entries in response: %d", len(output.Entries))
+	}
+	result := output.Entries[0]
+	if output.FailedEntryCount > 0 || result.ErrorCode != nil {
+		return "", fmt.Errorf("event was rejected: %s: %s", aws.ToString(result.ErrorCode), aws.ToString(result.ErrorMessage))
+	}
+	return aws.ToString(result.EventId), nil
+}
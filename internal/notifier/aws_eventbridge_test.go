@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewAWSEventBridge(t *testing.T) {
+	tests := []struct {
+		name        string
+		eventBus    string
+		source      string
+		secretData  map[string][]byte
+		envRegion   string
+		expectedErr string
+		region      string
+	}{
+		{
+			name:        "empty event bus",
+			expectedErr: "AWS EventBridge event bus (address) cannot be empty",
+		},
+		{
+			name:        "AWS source",
+			eventBus:    "default",
+			source:      "aws.flux",
+			expectedErr: "cannot start with 'aws.'",
+		},
+		{
+			name:        "not an event bus ARN",
+			eventBus:    "arn:aws:sns:us-east-1:123456789012:flux-events",
+			expectedErr: "resource must be an events event-bus",
+		},
+		{
+			name:        "event bus name without region",
+			eventBus:    "flux",
+			expectedErr: "AWS region cannot be empty",
+		},
+		{
+			name:     "region of the event bus ARN",
+			eventBus: "arn:aws:events:eu-west-1:123456789012:event-bus/ops",
+			region:   "eu-west-1",
+		},
+		{
+			name:      "event bus name with region of the environment",
+			eventBus:  "flux",
+			envRegion: "ap-southeast-2",
+			region:    "ap-southeast-2",
+		},
+		{
+			name:       "event bus name with region",
+			eventBus:   "flux",
+			secretData: map[string][]byte{"region": []byte("us-east-2")},
+			region:     "us-east-2",
+		},
+	}
+
+	isolateAWSEnvironment(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv("AWS_REGION", tt.envRegion)
+
+			provider, err := NewAWSEventBridge(tt.eventBus, tt.source, "", nil, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(provider.client.(*awsEventBridgeClient).client.Options().Region).To(Equal(tt.region))
+		})
+	}
+}
+
+func TestAWSEventBridgePost(t *testing.T) {
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Kustomization",
+			Namespace: "flux-system",
+			Name:      "apps",
+		},
+		Severity:            eventv1.EventSeverityError,
+		Timestamp:           metav1.Unix(1700000000, 0),
+		Reason:              "HealthCheckFailed",
+		Message:             "health check failed",
+		ReportingController: "kustomize-controller",
+	}
+	eventBus := "arn:aws:events:eu-west-1:123456789012:event-bus/ops"
+
+	tests := []struct {
+		name               string
+		source             string
+		event              eventv1.Event
+		response           string
+		statusCode         int
+		expectedSource     string
+		expectedDetailType string
+		expectedErr        string
+	}{
+		{
+			name:               "default source",
+			event:              event,
+			response:           `{"FailedEntryCount":0,"Entries":[{"EventId":"11710aed-b79e-4468-a20b-bb3c0c3b4860"}]}`,
+			expectedSource:     "fluxcd.kustomize-controller",
+			expectedDetailType: "HealthCheckFailed",
+		},
+		{
+			name:               "custom source",
+			source:             "com.example.flux",
+			event:              eventv1.Event{Message: "test"},
+			response:           `{"FailedEntryCount":0,"Entries":[{"EventId":"11710aed-b79e-4468-a20b-bb3c0c3b4860"}]}`,
+			expectedSource:     "com.example.flux",
+			expectedDetailType: awsEventBridgeDefaultDetailType,
+		},
+		{
+			name:               "rejected entry",
+			event:              event,
+			response:           `{"FailedEntryCount":1,"Entries":[{"ErrorCode":"InternalFailure","ErrorMessage":"try again"}]}`,
+			expectedSource:     "fluxcd.kustomize-controller",
+			expectedDetailType: "HealthCheckFailed",
+			expectedErr:        "event was rejected: InternalFailure: try again",
+		},
+		{
+			name:               "API error",
+			event:              event,
+			statusCode:         http.StatusBadRequest,
+			response:           `{"__type":"com.amazonaws.events#ResourceNotFoundException","message":"Event bus ops does not exist."}`,
+			expectedSource:     "fluxcd.kustomize-controller",
+			expectedDetailType: "HealthCheckFailed",
+			expectedErr:        "api error ResourceNotFoundException: Event bus ops does not exist.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var request struct {
+				Entries []struct {
+					EventBusName string
+					Source       string
+					DetailType   string
+					Detail       string
+					Time         float64
+				}
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				g.Expect(r.Header.Get("X-Amz-Target")).To(Equal("AWSEvents.PutEvents"))
+				g.Expect(r.Header.Get("Authorization")).To(ContainSubstring("/eu-west-1/events/aws4_request"))
+				g.Expect(json.NewDecoder(r.Body).Decode(&request)).To(Succeed())
+				if tt.statusCode != 0 {
+					w.WriteHeader(tt.statusCode)
+				}
+				w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			provider, err := NewAWSEventBridge(eventBus, tt.source, "", nil, testAWSSecretData(srv.URL))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = provider.Post(context.Background(), tt.event)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			g.Expect(request.Entries).To(HaveLen(1))
+			entry := request.Entries[0]
+			g.Expect(entry.EventBusName).To(Equal(eventBus))
+			g.Expect(entry.Source).To(Equal(tt.expectedSource))
+			g.Expect(entry.DetailType).To(Equal(tt.expectedDetailType))
+
+			var detail eventv1.Event
+			g.Expect(json.Unmarshal([]byte(entry.Detail), &detail)).To(Succeed())
+			g.Expect(detail.Message).To(Equal(tt.event.Message))
+		})
+	}
+}
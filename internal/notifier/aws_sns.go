@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type (
+	// AWSSNS holds an AWS SNS client and target topic.
+	AWSSNS struct {
+		egress
+
+		topicARN string
+		fifo     bool
+		headers  map[string]string
+		client   interface {
+			publish(ctx context.Context, input *sns.PublishInput) (messageID string, err error)
+		}
+	}
+
+	awsSNSClient struct {
+		client *sns.Client
+	}
+)
+
+// ensure *AWSSNS implements Interface.
+var _ Interface = &AWSSNS{}
+
+// NewAWSSNS creates an AWS SNS notifier publishing to the topic of the given
+// ARN, in the region of the topic unless overridden in the secret data.
+func NewAWSSNS(topicARN, proxyURL string, headers map[string]string,
+	certPool *x509.CertPool, secretData map[string][]byte) (*AWSSNS, error) {
+	if topicARN == "" {
+		return nil, errors.New("AWS SNS topic ARN (address) cannot be empty")
+	}
+	topic, err := arn.Parse(topicARN)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AWS SNS topic ARN '%s': %w", topicARN, err)
+	}
+	if topic.Service != "sns" {
+		return nil, fmt.Errorf("invalid AWS SNS topic ARN '%s': service must be sns", topicARN)
+	}
+
+	opts, err := newAWSOptions(topic.Region, proxyURL, certPool, secretData)
+	if err != nil {
+		return nil, err
+	}
+	s := &AWSSNS{
+		topicARN: topicARN,
+		fifo:     strings.HasSuffix(topic.Resource, ".fifo"),
+		headers:  headers,
+	}
+	cfg, err := opts.loadConfig(context.Background(), &s.egress)
+	if err != nil {
+		return nil, err
+	}
+	s.client = &awsSNSClient{client: sns.NewFromConfig(cfg)}
+	return s, nil
+}
+
+// Post publishes Flux events to an AWS SNS topic.
+func (s *AWSSNS) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	eventPayload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error json-marshaling event: %w", err)
+	}
+
+	input := &sns.PublishInput{
+		TopicArn:          aws.String(s.topicARN),
+		Message:           aws.String(string(eventPayload)),
+		MessageAttributes: make(map[string]snstypes.MessageAttributeValue),
+	}
+	for key, value := range awsMessageAttributes(event, s.headers) {
+		input.MessageAttributes[key] = snstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	if s.fifo {
+		input.MessageGroupId = aws.String(awsMessageGroupID(event))
+		input.MessageDeduplicationId = aws.String(awsMessageDeduplicationID(eventPayload))
+	}
+
+	messageID, err := s.client.publish(ctx, input)
+	if err != nil {
+		return fmt.Errorf("error publishing event to topic %s: %w", s.topicARN, err)
+	}
+
+	// debug log
+	log.FromContext(ctx).V(1).Info("Event published to AWS SNS topic",
+		"topic", s.topicARN,
+		"message id", messageID)
+
+	return nil
+}
+
+func (s *awsSNSClient) publish(ctx context.Context, input *sns.PublishInput) (messageID string, err error) {
+	output, err := s.client.Publish(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.MessageId), nil
+}
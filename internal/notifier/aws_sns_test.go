@@ -0,0 +1,207 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// snsPublishRequest is a Publish request received by the SNS stand-in.
+type snsPublishRequest struct {
+	host          string
+	authorization string
+	form          map[string]string
+	attributes    map[string]string
+}
+
+// newSNSStandIn returns an AWS SNS compatible server recording the Publish
+// requests of the query protocol.
+func newSNSStandIn(t *testing.T, requests chan<- snsPublishRequest) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := snsPublishRequest{
+			host:          r.Host,
+			authorization: r.Header.Get("Authorization"),
+			form:          make(map[string]string),
+			attributes:    make(map[string]string),
+		}
+		for key := range r.PostForm {
+			req.form[key] = r.PostForm.Get(key)
+		}
+		for i := 1; ; i++ {
+			name := r.PostForm.Get(fmt.Sprintf("MessageAttributes.entry.%d.Name", i))
+			if name == "" {
+				break
+			}
+			req.attributes[name] = r.PostForm.Get(fmt.Sprintf("MessageAttributes.entry.%d.Value.StringValue", i))
+		}
+		requests <- req
+
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprint(w, `<PublishResponse xmlns="http://sns.amazonaws.com/doc/2010-03-31/">
+  <PublishResult><MessageId>a8b5f1c2-6f7e-4a0e-9d4b-2f0f3f6c9f10</MessageId></PublishResult>
+  <ResponseMetadata><RequestId>f187a3c1-376f-11df-8963-01868b7c937a</RequestId></ResponseMetadata>
+</PublishResponse>`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewAWSSNS(t *testing.T) {
+	tests := []struct {
+		name        string
+		topicARN    string
+		secretData  map[string][]byte
+		expectedErr string
+		fifo        bool
+		region      string
+	}{
+		{
+			name:        "empty topic ARN",
+			expectedErr: "AWS SNS topic ARN (address) cannot be empty",
+		},
+		{
+			name:        "invalid topic ARN",
+			topicARN:    "flux-events",
+			expectedErr: "invalid AWS SNS topic ARN 'flux-events'",
+		},
+		{
+			name:        "not an SNS topic ARN",
+			topicARN:    "arn:aws:sqs:us-east-1:123456789012:flux-events",
+			expectedErr: "service must be sns",
+		},
+		{
+			name:        "partial static credentials",
+			topicARN:    "arn:aws:sns:us-east-1:123456789012:flux-events",
+			secretData:  map[string][]byte{"accessKeyID": []byte("AKID")},
+			expectedErr: "must have both the accessKeyID and secretAccessKey",
+		},
+		{
+			name:     "region of the topic",
+			topicARN: "arn:aws:sns:eu-west-1:123456789012:flux-events",
+			region:   "eu-west-1",
+		},
+		{
+			name:       "FIFO topic with region override",
+			topicARN:   "arn:aws:sns:eu-west-1:123456789012:flux-events.fifo",
+			secretData: map[string][]byte{"region": []byte("eu-central-1")},
+			fifo:       true,
+			region:     "eu-central-1",
+		},
+	}
+
+	isolateAWSEnvironment(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewAWSSNS(tt.topicARN, "", nil, nil, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(provider.fifo).To(Equal(tt.fifo))
+			g.Expect(provider.client.(*awsSNSClient).client.Options().Region).To(Equal(tt.region))
+		})
+	}
+}
+
+func TestAWSSNSPost(t *testing.T) {
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Kustomization",
+			Namespace: "flux-system",
+			Name:      "apps",
+		},
+		Severity: eventv1.EventSeverityError,
+		Reason:   "HealthCheckFailed",
+		Message:  "health check failed",
+	}
+
+	t.Run("standard topic", func(t *testing.T) {
+		g := NewWithT(t)
+
+		requests := make(chan snsPublishRequest, 1)
+		srv := newSNSStandIn(t, requests)
+		topicARN := "arn:aws:sns:us-east-1:123456789012:flux-events"
+		provider, err := NewAWSSNS(topicARN, "", map[string]string{"cluster": "prod"}, nil, testAWSSecretData(srv.URL))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+
+		req := <-requests
+		g.Expect(req.authorization).To(ContainSubstring("Credential=AKIDEXAMPLE/"))
+		g.Expect(req.authorization).To(ContainSubstring("/us-east-1/sns/aws4_request"))
+		g.Expect(req.form["Action"]).To(Equal("Publish"))
+		g.Expect(req.form["TopicArn"]).To(Equal(topicARN))
+		g.Expect(req.form).ToNot(HaveKey("MessageGroupId"))
+		g.Expect(req.attributes).To(Equal(map[string]string{
+			"cluster":   "prod",
+			"severity":  "error",
+			"kind":      "Kustomization",
+			"namespace": "flux-system",
+			"name":      "apps",
+		}))
+
+		var payload eventv1.Event
+		g.Expect(json.Unmarshal([]byte(req.form["Message"]), &payload)).To(Succeed())
+		g.Expect(payload.InvolvedObject).To(Equal(event.InvolvedObject))
+		g.Expect(payload.Message).To(Equal(event.Message))
+	})
+
+	t.Run("FIFO topic through proxy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		requests := make(chan snsPublishRequest, 1)
+		proxy := newSNSStandIn(t, requests)
+		provider, err := NewAWSSNS("arn:aws:sns:us-east-1:123456789012:flux-events.fifo", proxy.URL, nil, nil,
+			testAWSSecretData("http://sns.flux.invalid"))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+
+		req := <-requests
+		g.Expect(req.host).To(Equal("sns.flux.invalid"))
+		g.Expect(req.form["MessageGroupId"]).To(Equal("Kustomization/flux-system/apps"))
+		g.Expect(req.form["MessageDeduplicationId"]).To(HaveLen(64))
+	})
+
+	t.Run("commit status updates are dropped", func(t *testing.T) {
+		g := NewWithT(t)
+
+		provider, err := NewAWSSNS("arn:aws:sns:us-east-1:123456789012:flux-events", "", nil, nil, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		event := eventv1.Event{Metadata: map[string]string{
+			eventv1.MetaCommitStatusKey: eventv1.MetaCommitStatusUpdateValue,
+		}}
+		g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+	})
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+type (
+	// AWSSQS holds an AWS SQS client and target queue.
+	AWSSQS struct {
+		egress
+
+		queueURL string
+		fifo     bool
+		headers  map[string]string
+		client   interface {
+			sendMessage(ctx context.Context, input *sqs.SendMessageInput) (messageID string, err error)
+		}
+	}
+
+	awsSQSClient struct {
+		client *sqs.Client
+	}
+)
+
+// ensure *AWSSQS implements Interface.
+var _ Interface = &AWSSQS{}
+
+// NewAWSSQS creates an AWS SQS notifier sending to the queue of the given
+// URL, in the region of the queue unless overridden in the secret data.
+func NewAWSSQS(queueURL, proxyURL string, headers map[string]string,
+	certPool *x509.CertPool, secretData map[string][]byte) (*AWSSQS, error) {
+	if queueURL == "" {
+		return nil, errors.New("AWS SQS queue URL (address) cannot be empty")
+	}
+	queue, err := url.ParseRequestURI(queueURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AWS SQS queue URL '%s': %w", queueURL, err)
+	}
+
+	opts, err := newAWSOptions(awsSQSRegion(queue.Hostname()), proxyURL, certPool, secretData)
+	if err != nil {
+		return nil, err
+	}
+	s := &AWSSQS{
+		queueURL: queueURL,
+		fifo:     strings.HasSuffix(queue.Path, ".fifo"),
+		headers:  headers,
+	}
+	cfg, err := opts.loadConfig(context.Background(), &s.egress)
+	if err != nil {
+		return nil, err
+	}
+	s.client = &awsSQSClient{client: sqs.NewFromConfig(cfg)}
+	return s, nil
+}
+
+// awsSQSRegion returns the region of the given SQS endpoint host, e.g.
+// sqs.eu-west-1.amazonaws.com, or an empty string if unknown.
+func awsSQSRegion(host string) string {
+	parts := strings.Split(host, ".")
+	if len(parts) >= 4 && parts[0] == "sqs" && parts[2] == "amazonaws" {
+		return parts[1]
+	}
+	return ""
+}
+
+// Post sends Flux events to an AWS SQS queue.
+func (s *AWSSQS) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	eventPayload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error json-marshaling event: %w", err)
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:          aws.String(s.queueURL),
+		MessageBody:       aws.String(string(eventPayload)),
+		MessageAttributes: make(map[string]sqstypes.MessageAttributeValue),
+	}
+	for key, value := range awsMessageAttributes(event, s.headers) {
+		input.MessageAttributes[key] = sqstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+	if s.fifo {
+		input.MessageGroupId = aws.String(awsMessageGroupID(event))
+		input.MessageDeduplicationId = aws.String(awsMessageDeduplicationID(eventPayload))
+	}
+
+	messageID, err := s.client.sendMessage(ctx, input)
+	if err != nil {
+		return fmt.Errorf("error sending event to queue %s: %w", s.queueURL, err)
+	}
+
+	// debug log
+	log.FromContext(ctx).V(1).Info("Event sent to AWS SQS queue",
+		"queue", s.queueURL,
+		"message id", messageID)
+
+	return nil
+}
+
+func (s *awsSQSClient) sendMessage(ctx context.Context, input *sqs.SendMessageInput) (messageID string, err error) {
+	output, err := s.client.SendMessage(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.MessageId), nil
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// sqsSendMessageRequest is a SendMessage request received by the SQS stand-in.
+type sqsSendMessageRequest struct {
+	target        string
+	authorization string
+
+	QueueUrl          string
+	MessageBody       string
+	MessageGroupId    string
+	MessageAttributes map[string]struct {
+		DataType    string
+		StringValue string
+	}
+}
+
+// newSQSStandIn returns an AWS SQS compatible server recording the
+// SendMessage requests of the JSON protocol.
+func newSQSStandIn(t *testing.T, requests chan<- sqsSendMessageRequest) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req sqsSendMessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req.target = r.Header.Get("X-Amz-Target")
+		req.authorization = r.Header.Get("Authorization")
+		requests <- req
+
+		sum := md5.Sum([]byte(req.MessageBody))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		json.NewEncoder(w).Encode(map[string]string{
+			"MessageId":        "5fea7756-0ea4-451a-a703-a558b933e274",
+			"MD5OfMessageBody": hex.EncodeToString(sum[:]),
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestNewAWSSQS(t *testing.T) {
+	tests := []struct {
+		name        string
+		queueURL    string
+		secretData  map[string][]byte
+		expectedErr string
+		fifo        bool
+		region      string
+	}{
+		{
+			name:        "empty queue URL",
+			expectedErr: "AWS SQS queue URL (address) cannot be empty",
+		},
+		{
+			name:        "invalid queue URL",
+			queueURL:    "flux-events",
+			expectedErr: "invalid AWS SQS queue URL 'flux-events'",
+		},
+		{
+			name:        "unknown region",
+			queueURL:    "http://localstack:4566/000000000000/flux-events",
+			expectedErr: "AWS region cannot be empty",
+		},
+		{
+			name:     "region of the queue",
+			queueURL: "https://sqs.eu-west-1.amazonaws.com/123456789012/flux-events",
+			region:   "eu-west-1",
+		},
+		{
+			name:       "FIFO queue with region",
+			queueURL:   "http://localstack:4566/000000000000/flux-events.fifo",
+			secretData: map[string][]byte{"region": []byte("us-east-1")},
+			fifo:       true,
+			region:     "us-east-1",
+		},
+	}
+
+	isolateAWSEnvironment(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewAWSSQS(tt.queueURL, "", nil, nil, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(provider.fifo).To(Equal(tt.fifo))
+			g.Expect(provider.client.(*awsSQSClient).client.Options().Region).To(Equal(tt.region))
+		})
+	}
+}
+
+func TestAWSSQSPost(t *testing.T) {
+	g := NewWithT(t)
+
+	requests := make(chan sqsSendMessageRequest, 1)
+	srv := newSQSStandIn(t, requests)
+	queueURL := "https://sqs.eu-west-1.amazonaws.com/123456789012/flux-events.fifo"
+	provider, err := NewAWSSQS(queueURL, "", nil, nil, testAWSSecretData(srv.URL))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "HelmRelease",
+			Namespace: "apps",
+			Name:      "podinfo",
+		},
+		Severity: eventv1.EventSeverityInfo,
+		Reason:   "UpgradeSucceeded",
+		Message:  "Helm upgrade succeeded",
+	}
+	g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+
+	req := <-requests
+	g.Expect(req.target).To(Equal("AmazonSQS.SendMessage"))
+	g.Expect(req.authorization).To(ContainSubstring("/eu-west-1/sqs/aws4_request"))
+	g.Expect(req.QueueUrl).To(Equal(queueURL))
+	g.Expect(req.MessageGroupId).To(Equal("HelmRelease/apps/podinfo"))
+	g.Expect(req.MessageAttributes).To(HaveLen(4))
+	g.Expect(req.MessageAttributes["severity"].StringValue).To(Equal("info"))
+	g.Expect(req.MessageAttributes["kind"].StringValue).To(Equal("HelmRelease"))
+	g.Expect(req.MessageAttributes["namespace"].DataType).To(Equal("String"))
+
+	var payload eventv1.Event
+	g.Expect(json.Unmarshal([]byte(req.MessageBody), &payload)).To(Succeed())
+	g.Expect(payload.InvolvedObject).To(Equal(event.InvolvedObject))
+
+	commitStatus := eventv1.Event{Metadata: map[string]string{
+		eventv1.MetaCommitStatusKey: eventv1.MetaCommitStatusUpdateValue,
+	}}
+	g.Expect(provider.Post(context.Background(), commitStatus)).To(Succeed())
+	g.Expect(requests).To(BeEmpty())
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// isolateAWSEnvironment clears the region and shared config of the
+// environment, for the AWS config to only be loaded from the test options.
+func isolateAWSEnvironment(t *testing.T) {
+	t.Helper()
+	missing := filepath.Join(t.TempDir(), "missing")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_CONFIG_FILE", missing)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", missing)
+}
+
+// testAWSSecretData returns the secret data of an AWS Provider with static
+// credentials and the given endpoint.
+func testAWSSecretData(endpoint string) map[string][]byte {
+	return map[string][]byte{
+		"accessKeyID":     []byte("AKIDEXAMPLE"),
+		"secretAccessKey": []byte("s3cr3t"),
+		"endpoint":        []byte(endpoint),
+	}
+}
+
+func Test_newAWSOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		region      string
+		proxyURL    string
+		secretData  map[string][]byte
+		expectedErr string
+	}{
+		{
+			name:        "invalid endpoint",
+			region:      "us-east-1",
+			secretData:  map[string][]byte{"endpoint": []byte("localstack")},
+			expectedErr: "invalid AWS endpoint",
+		},
+		{
+			name:        "invalid proxy",
+			region:      "us-east-1",
+			proxyURL:    "http://proxy:port",
+			expectedErr: "failed to parse proxy URL",
+		},
+		{
+			name:       "static credentials",
+			region:     "us-east-1",
+			proxyURL:   "http://proxy:8080",
+			secretData: testAWSSecretData("http://localstack:4566"),
+		},
+	}
+
+	isolateAWSEnvironment(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts, err := newAWSOptions(tt.region, tt.proxyURL, nil, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(opts.endpoint).To(Equal("http://localstack:4566"))
+			g.Expect(opts.accessKeyID).To(Equal("AKIDEXAMPLE"))
+			g.Expect(opts.proxyURL.Host).To(Equal("proxy:8080"))
+		})
+	}
+}
+
+func Test_awsOptions_loadConfig(t *testing.T) {
+	tests := []struct {
+		name           string
+		region         string
+		secretData     map[string][]byte
+		envRegion      string
+		expectedRegion string
+		expectedErr    string
+	}{
+		{
+			name:        "empty region",
+			expectedErr: "AWS region cannot be empty",
+		},
+		{
+			name:           "region of the environment",
+			envRegion:      "ap-southeast-2",
+			expectedRegion: "ap-southeast-2",
+		},
+		{
+			name:           "region of the resource",
+			region:         "eu-west-1",
+			envRegion:      "ap-southeast-2",
+			expectedRegion: "eu-west-1",
+		},
+		{
+			name:           "region of the secret",
+			region:         "eu-west-1",
+			secretData:     map[string][]byte{"region": []byte("us-east-2")},
+			envRegion:      "ap-southeast-2",
+			expectedRegion: "us-east-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			isolateAWSEnvironment(t)
+			t.Setenv("AWS_REGION", tt.envRegion)
+
+			opts, err := newAWSOptions(tt.region, "", nil, tt.secretData)
+			g.Expect(err).ToNot(HaveOccurred())
+			cfg, err := opts.loadConfig(context.Background(), nil)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cfg.Region).To(Equal(tt.expectedRegion))
+		})
+	}
+}
@@ -18,10 +18,10 @@ package notifier
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v6"
@@ -40,6 +40,8 @@ type azureDevOpsClient interface {
 
 // AzureDevOps is an Azure DevOps notifier.
 type AzureDevOps struct {
+	egress
+
 	Project      string
 	Repo         string
 	CommitStatus string
@@ -71,22 +73,18 @@ func NewAzureDevOps(commitStatus string, addr string, token string, certPool *x5
 	repo := comp[3]
 
 	orgURL := fmt.Sprintf("%v/%v", host, org)
-	connection := azuredevops.NewPatConnection(orgURL, token)
-	if certPool != nil {
-		connection.TlsConfig = &tls.Config{
-			RootCAs: certPool,
-		}
-	}
-	client := connection.GetClientByUrl(orgURL)
-	gitClient := &git.ClientImpl{
-		Client: *client,
-	}
-	return &AzureDevOps{
+	a := &AzureDevOps{
 		Project:      proj,
 		Repo:         repo,
 		CommitStatus: commitStatus,
-		Client:       gitClient,
-	}, nil
+	}
+	connection := azuredevops.NewPatConnection(orgURL, token)
+	hc := &http.Client{Transport: a.newHTTPTransport(certPool)}
+	client := azuredevops.NewClientWithOptions(connection, orgURL, azuredevops.WithHTTPClient(hc))
+	a.Client = &git.ClientImpl{
+		Client: *client,
+	}
+	return a, nil
 }
 
 // Post Azure DevOps commit status
@@ -17,20 +17,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"os"
 
 	"github.com/Azure/azure-amqp-common-go/v4/auth"
+	"github.com/Azure/azure-amqp-common-go/v4/conn"
 	eventhub "github.com/Azure/azure-event-hubs-go/v3"
+	"github.com/Azure/go-autorest/autorest/azure"
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 )
 
 // AzureEventHub holds the eventhub client
 type AzureEventHub struct {
+	egress
+
 	Hub *eventhub.Hub
+	// host is the host of the Event Hubs namespace, checked against the
+	// egress policy before connecting, as the eventhub client has no dialer
+	// hook.
+	host string
 }
 
 // NewAzureEventHub creates a eventhub client
 func NewAzureEventHub(endpointURL, token, eventHubNamespace string) (*AzureEventHub, error) {
 	var hub *eventhub.Hub
+	var host string
 	var err error
 
 	// token should only be defined if JWT is used
@@ -39,15 +50,24 @@ func NewAzureEventHub(endpointURL, token, eventHubNamespace string) (*AzureEvent
 		if err != nil {
 			return nil, fmt.Errorf("failed to create a eventhub using JWT %v", err)
 		}
+		host, err = jwtHubHost(eventHubNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a eventhub using JWT %v", err)
+		}
 	} else {
 		hub, err = newSASHub(endpointURL)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create a eventhub using SAS %v", err)
 		}
+		host, err = sasHubHost(endpointURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a eventhub using SAS %v", err)
+		}
 	}
 
 	return &AzureEventHub{
-		Hub: hub,
+		Hub:  hub,
+		host: host,
 	}, nil
 }
 
@@ -63,6 +83,12 @@ func (e *AzureEventHub) Post(ctx context.Context, event eventv1.Event) error {
 		return fmt.Errorf("unable to marshall event: %w", err)
 	}
 
+	if policy := e.egressPolicy(); policy != nil {
+		if err := policy.checkHost(ctx, e.host); err != nil {
+			return fmt.Errorf("failed to send msg: %w", err)
+		}
+	}
+
 	err = e.Hub.Send(ctx, eventhub.NewEvent(eventBytes))
 	if err != nil {
 		return fmt.Errorf("failed to send msg: %w", err)
@@ -116,3 +142,30 @@ func newSASHub(address string) (*eventhub.Hub, error) {
 
 	return hub, nil
 }
+
+// jwtHubHost returns the host of the given Event Hubs namespace, in the
+// Azure environment set by AZURE_ENVIRONMENT as in eventhub.NewHub.
+func jwtHubHost(eventHubNamespace string) (string, error) {
+	env := azure.PublicCloud
+	if name := os.Getenv("AZURE_ENVIRONMENT"); name != "" {
+		var err error
+		if env, err = azure.EnvironmentFromName(name); err != nil {
+			return "", err
+		}
+	}
+	return eventHubNamespace + "." + env.ServiceBusEndpointSuffix, nil
+}
+
+// sasHubHost returns the host of the Event Hubs namespace of the given SAS
+// ConnectionString.
+func sasHubHost(address string) (string, error) {
+	parsed, err := conn.ParsedConnectionFromStr(address)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(parsed.Host)
+	if err != nil {
+		return "", err
+	}
+	return u.Hostname(), nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestNewAzureEventHub_host(t *testing.T) {
+	e, err := NewAzureEventHub("hub", "jwt", "flux")
+	require.NoError(t, err)
+	require.Equal(t, "flux.servicebus.windows.net", e.host)
+
+	t.Setenv("AZURE_ENVIRONMENT", "AzureChinaCloud")
+	e, err = NewAzureEventHub("hub", "jwt", "flux")
+	require.NoError(t, err)
+	require.Equal(t, "flux.servicebus.chinacloudapi.cn", e.host)
+
+	e, err = NewAzureEventHub("Endpoint=sb://flux.servicebus.windows.net/;SharedAccessKeyName=key;SharedAccessKey=secret;EntityPath=hub", "", "")
+	require.NoError(t, err)
+	require.Equal(t, "flux.servicebus.windows.net", e.host)
+}
+
+func TestAzureEventHub_egressDenied(t *testing.T) {
+	lookupNetIP = func(ctx context.Context, network, host string) ([]netip.Addr, error) {
+		return []netip.Addr{netip.MustParseAddr("10.0.0.1")}, nil
+	}
+	t.Cleanup(func() { lookupNetIP = net.DefaultResolver.LookupNetIP })
+
+	n, err := NewFactory("hub", WithToken("jwt"), WithChannel("flux"),
+		WithEgressPolicy(newEgressPolicy(t, EgressPolicyOptions{BlockPrivateNetworks: true}))).
+		Notifier(apiv1.AzureEventHubProvider)
+	require.NoError(t, err)
+	err = n.Post(context.Background(), eventv1.Event{})
+	require.ErrorIs(t, err, ErrEgressDenied)
+	require.ErrorContains(t, err, "flux.servicebus.windows.net")
+}
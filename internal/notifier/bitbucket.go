@@ -18,7 +18,6 @@ package notifier
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"errors"
@@ -34,6 +33,8 @@ import (
 
 // Bitbucket is a Bitbucket Server notifier.
 type Bitbucket struct {
+	egress
+
 	Owner        string
 	Repo         string
 	CommitStatus string
@@ -70,23 +71,14 @@ func NewBitbucket(commitStatus string, addr string, token string, certPool *x509
 	owner := comp[0]
 	repo := comp[1]
 
-	client := bitbucket.NewBasicAuth(username, password)
-	if certPool != nil {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs: certPool,
-			},
-		}
-		hc := &http.Client{Transport: tr}
-		client.HttpClient = hc
-	}
-
-	return &Bitbucket{
+	b := &Bitbucket{
 		Owner:        owner,
 		Repo:         repo,
 		CommitStatus: commitStatus,
-		Client:       client,
-	}, nil
+		Client:       bitbucket.NewBasicAuth(username, password),
+	}
+	b.Client.HttpClient = &http.Client{Transport: b.newHTTPTransport(certPool)}
+	return b, nil
 }
 
 // Post Bitbucket commit status
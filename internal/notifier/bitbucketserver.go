@@ -37,6 +37,8 @@ import (
 
 // BitbucketServer is a notifier for BitBucket Server and Data Center.
 type BitbucketServer struct {
+	egress
+
 	CommitStatus    string
 	Url             *url.URL
 	ProviderAddress string
@@ -92,6 +94,19 @@ func NewBitbucketServer(commitStatus string, addr string, token string, certPool
 		return nil, errors.New("commit status cannot be empty")
 	}
 
+	if len(token) == 0 && (len(username) == 0 || len(password) == 0) {
+		return nil, errors.New("invalid credentials, expected to be one of username/password or API Token")
+	}
+
+	b := &BitbucketServer{
+		CommitStatus:    commitStatus,
+		Url:             url,
+		ProviderAddress: addr,
+		Token:           token,
+		Username:        username,
+		Password:        password,
+	}
+
 	httpClient := retryablehttp.NewClient()
 	if certPool != nil {
 		httpClient.HTTPClient.Transport = &http.Transport{
@@ -100,6 +115,7 @@ func NewBitbucketServer(commitStatus string, addr string, token string, certPool
 			},
 		}
 	}
+	b.enforceEgressPolicy(httpClient)
 
 	httpClient.HTTPClient.Timeout = 15 * time.Second
 	httpClient.RetryWaitMin = 2 * time.Second
@@ -107,19 +123,8 @@ func NewBitbucketServer(commitStatus string, addr string, token string, certPool
 	httpClient.RetryMax = 4
 	httpClient.Logger = nil
 
-	if len(token) == 0 && (len(username) == 0 || len(password) == 0) {
-		return nil, errors.New("invalid credentials, expected to be one of username/password or API Token")
-	}
-
-	return &BitbucketServer{
-		CommitStatus:    commitStatus,
-		Url:             url,
-		ProviderAddress: addr,
-		Token:           token,
-		Username:        username,
-		Password:        password,
-		Client:          httpClient,
-	}, nil
+	b.Client = httpClient
+	return b, nil
 }
 
 // Post Bitbucket Server build status
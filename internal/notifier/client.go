@@ -18,6 +18,7 @@ package notifier
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -27,14 +28,154 @@ import (
 	"net/http"
 	"net/url"
 	"runtime"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 type requestOptFunc func(*retryablehttp.Request)
 
-func postMessage(ctx context.Context, address, proxy string, certPool *x509.CertPool, payload interface{}, reqOpts ...requestOptFunc) error {
+const (
+	// httpClientPoolMaxSize is the maximum number of HTTP clients kept
+	// in the pool.
+	httpClientPoolMaxSize = 256
+	// httpClientPoolIdleTimeout is the duration after which an unused
+	// HTTP client is removed from the pool.
+	httpClientPoolIdleTimeout = 10 * time.Minute
+)
+
+// httpClientKey identifies the HTTP clients that can be shared, as they
+// are configured with the same proxy, CA certificates and egress policy.
+// The CA certificates pools parsed with NewCertPool are shared per digest of
+// their PEM bytes, hence so are the clients using them.
+type httpClientKey struct {
+	proxy        string
+	certPool     *x509.CertPool
+	egressPolicy *EgressPolicy
+}
+
+type pooledHTTPClient struct {
+	client   *retryablehttp.Client
+	lastUsed time.Time
+}
+
+// httpClientPool holds the HTTP clients used by postMessage, so that their
+// connections are reused across notifications sent with the same proxy, CA
+// certificates and egress policy.
+type httpClientPool struct {
+	mu      sync.Mutex
+	clients map[httpClientKey]*pooledHTTPClient
+}
+
+var httpClients = &httpClientPool{clients: make(map[httpClientKey]*pooledHTTPClient)}
+
+// get returns the pooled HTTP client for the given proxy, CA certificates
+// and egress policy, creating it if needed. Unused clients are removed from
+// the pool, with their idle connections closed.
+func (p *httpClientPool) get(proxy string, certPool *x509.CertPool, egressPolicy *EgressPolicy) (*retryablehttp.Client, error) {
+	key := httpClientKey{proxy: proxy, certPool: certPool, egressPolicy: egressPolicy}
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if c, ok := p.clients[key]; ok {
+		c.lastUsed = now
+		return c.client, nil
+	}
+
+	client, err := newRetryableHTTPClient(proxy, certPool, egressPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var oldestKey httpClientKey
+	var oldest *pooledHTTPClient
+	for k, c := range p.clients {
+		if now.Sub(c.lastUsed) > httpClientPoolIdleTimeout {
+			p.remove(k)
+			continue
+		}
+		if oldest == nil || c.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = k, c
+		}
+	}
+	if len(p.clients) >= httpClientPoolMaxSize && oldest != nil {
+		p.remove(oldestKey)
+	}
+
+	p.clients[key] = &pooledHTTPClient{client: client, lastUsed: now}
+	return client, nil
+}
+
+func (p *httpClientPool) remove(key httpClientKey) {
+	p.clients[key].client.HTTPClient.CloseIdleConnections()
+	delete(p.clients, key)
+}
+
+type cachedCertPool struct {
+	pool     *x509.CertPool
+	lastUsed time.Time
+}
+
+// certPoolCache holds the CA certificates pools returned by NewCertPool, by
+// the SHA-256 digest of the PEM bytes they were parsed from.
+type certPoolCache struct {
+	mu    sync.Mutex
+	pools map[[sha256.Size]byte]*cachedCertPool
+}
+
+var certPools = &certPoolCache{pools: make(map[[sha256.Size]byte]*cachedCertPool)}
+
+// NewCertPool returns a pool of the CA certificates in the given PEM bytes.
+// The same pool is returned for the same PEM bytes, so that the HTTP clients
+// trusting these certificates are shared across notifiers.
+func NewCertPool(pem []byte) (*x509.CertPool, error) {
+	return certPools.get(pem)
+}
+
+// get returns the cached pool parsed from the given PEM bytes, parsing it if
+// needed. Unused pools are removed from the cache.
+func (c *certPoolCache) get(pem []byte) (*x509.CertPool, error) {
+	key := sha256.Sum256(pem)
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.pools[key]; ok {
+		p.lastUsed = now
+		return p.pool, nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("could not append to cert pool")
+	}
+
+	var oldestKey [sha256.Size]byte
+	var oldest *cachedCertPool
+	for k, p := range c.pools {
+		if now.Sub(p.lastUsed) > httpClientPoolIdleTimeout {
+			delete(c.pools, k)
+			continue
+		}
+		if oldest == nil || p.lastUsed.Before(oldest.lastUsed) {
+			oldestKey, oldest = k, p
+		}
+	}
+	if len(c.pools) >= httpClientPoolMaxSize && oldest != nil {
+		delete(c.pools, oldestKey)
+	}
+
+	c.pools[key] = &cachedCertPool{pool: pool, lastUsed: now}
+	return pool, nil
+}
+
+func newRetryableHTTPClient(proxy string, certPool *x509.CertPool, egressPolicy *EgressPolicy) (*retryablehttp.Client, error) {
 	httpClient := retryablehttp.NewClient()
 	if certPool != nil {
 		httpClient.HTTPClient.Transport = &http.Transport{
@@ -47,7 +188,7 @@ func postMessage(ctx context.Context, address, proxy string, certPool *x509.Cert
 	if proxy != "" {
 		proxyURL, err := url.Parse(proxy)
 		if err != nil {
-			return fmt.Errorf("unable to parse proxy URL '%s', error: %w", proxy, err)
+			return nil, fmt.Errorf("unable to parse proxy URL '%s', error: %w", proxy, err)
 		}
 		var tlsConfig *tls.Config
 		if certPool != nil {
@@ -78,11 +219,120 @@ func postMessage(ctx context.Context, address, proxy string, certPool *x509.Cert
 	httpClient.RetryWaitMax = 30 * time.Second
 	httpClient.RetryMax = 4
 	httpClient.Logger = nil
+	httpClient.ErrorHandler = retryAfterErrorHandler
+	(&egress{policy: egressPolicy}).enforceEgressPolicy(httpClient)
 
+	// Trace the requests, propagating the trace context to the provider.
+	httpClient.HTTPClient.Transport = &tracingTransport{
+		Transport: otelhttp.NewTransport(httpClient.HTTPClient.Transport),
+		base:      httpClient.HTTPClient.Transport,
+	}
+
+	return httpClient, nil
+}
+
+// tracingTransport traces the requests sent with the base transport, whose
+// idle connections can still be closed.
+type tracingTransport struct {
+	*otelhttp.Transport
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) CloseIdleConnections() {
+	if c, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		c.CloseIdleConnections()
+	}
+}
+
+// RetryAfterError is returned when a provider asked, with a Retry-After
+// header, to wait before sending further requests.
+type RetryAfterError struct {
+	// After is the duration to wait before sending further requests.
+	After time.Duration
+	Err   error
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError is returned when a provider responds with an unexpected HTTP
+// status code.
+type StatusError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// retryAfterErrorHandler returns the error of the last attempt when the
+// retries are exhausted, as a RetryAfterError if the last response has a
+// Retry-After header.
+func retryAfterErrorHandler(resp *http.Response, err error, numTries int) (*http.Response, error) {
+	if err == nil {
+		err = fmt.Errorf("giving up after %d attempt(s)", numTries)
+	} else {
+		err = fmt.Errorf("giving up after %d attempt(s): %w", numTries, err)
+	}
+	if resp == nil {
+		return nil, err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	err = &StatusError{StatusCode: resp.StatusCode, Err: err}
+	if after, ok := parseRetryAfter(resp); ok {
+		return nil, &RetryAfterError{After: after, Err: err}
+	}
+	return nil, err
+}
+
+// parseRetryAfter returns the duration from the Retry-After header of the
+// given 429 or 503 response, in seconds or HTTP date format.
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(header, 10, 64); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func postMessage(ctx context.Context, address, proxy string, certPool *x509.CertPool, egressPolicy *EgressPolicy,
+	payload interface{}, reqOpts ...requestOptFunc) error {
 	data, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("marshalling notification payload failed: %w", err)
 	}
+	return postData(ctx, address, proxy, certPool, egressPolicy, data, reqOpts...)
+}
+
+// postData sends the given JSON data, for the payloads that are not a single
+// JSON value.
+func postData(ctx context.Context, address, proxy string, certPool *x509.CertPool, egressPolicy *EgressPolicy,
+	data []byte, reqOpts ...requestOptFunc) error {
+	httpClient, err := httpClients.get(proxy, certPool, egressPolicy)
+	if err != nil {
+		return err
+	}
 
 	req, err := retryablehttp.NewRequest(http.MethodPost, address, data)
 	if err != nil {
@@ -99,13 +349,22 @@ func postMessage(ctx context.Context, address, proxy string, certPool *x509.Cert
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
+	// Drain and close the body for the connection to be reused.
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusCreated {
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusCreated &&
+		resp.StatusCode != http.StatusNoContent {
 		b, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return fmt.Errorf("unable to read response body, %s", err)
 		}
-		return fmt.Errorf("request failed with status code %d, %s", resp.StatusCode, string(b))
+		return &StatusError{
+			StatusCode: resp.StatusCode,
+			Err:        fmt.Errorf("request failed with status code %d, %s", resp.StatusCode, string(b)),
+		}
 	}
 
 	return nil
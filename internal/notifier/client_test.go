@@ -20,9 +20,12 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -45,7 +48,7 @@ func Test_postMessage(t *testing.T) {
 		require.Equal(t, "success", payload["status"])
 	}))
 	defer ts.Close()
-	err := postMessage(context.Background(), ts.URL, "", nil, map[string]string{"status": "success"})
+	err := postMessage(context.Background(), ts.URL, "", nil, nil, map[string]string{"status": "success"})
 	require.NoError(t, err)
 }
 
@@ -56,7 +59,7 @@ func Test_postMessage_timeout(t *testing.T) {
 	defer ts.Close()
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
 	defer cancel()
-	err := postMessage(ctx, ts.URL, "", nil, map[string]string{"status": "success"})
+	err := postMessage(ctx, ts.URL, "", nil, nil, map[string]string{"status": "success"})
 	require.Error(t, err, "context deadline exceeded")
 }
 
@@ -77,10 +80,149 @@ func Test_postSelfSignedCert(t *testing.T) {
 	require.NoError(t, err)
 	certpool := x509.NewCertPool()
 	certpool.AddCert(cert)
-	err = postMessage(context.Background(), ts.URL, "", certpool, map[string]string{"status": "success"})
+	err = postMessage(context.Background(), ts.URL, "", certpool, nil, map[string]string{"status": "success"})
 	require.NoError(t, err)
 }
 
+func Test_postMessage_reusesConnections(t *testing.T) {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad request"))
+	}))
+	var conns atomic.Int32
+	ts.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	ts.Start()
+	defer ts.Close()
+
+	for range 3 {
+		err := postMessage(context.Background(), ts.URL, "", nil, nil, map[string]string{"status": "success"})
+		require.ErrorContains(t, err, "status code 400")
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+		require.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+	}
+	require.Equal(t, int32(1), conns.Load())
+}
+
+func Test_postMessage_retryAfter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	err := postMessage(context.Background(), ts.URL, "", nil, nil, map[string]string{"status": "success"})
+	var retryAfter *RetryAfterError
+	require.ErrorAs(t, err, &retryAfter)
+	require.Equal(t, time.Duration(0), retryAfter.After)
+	require.ErrorContains(t, err, "giving up after 5 attempt(s)")
+	var statusErr *StatusError
+	require.ErrorAs(t, err, &statusErr)
+	require.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		header     string
+		want       time.Duration
+		wantOK     bool
+	}{
+		{name: "seconds", statusCode: http.StatusTooManyRequests, header: "120", want: 2 * time.Minute, wantOK: true},
+		{name: "past date", statusCode: http.StatusServiceUnavailable, header: "Fri, 31 Dec 1999 23:59:59 GMT", want: 0, wantOK: true},
+		{name: "invalid", statusCode: http.StatusTooManyRequests, header: "soon"},
+		{name: "missing", statusCode: http.StatusTooManyRequests},
+		{name: "other status", statusCode: http.StatusInternalServerError, header: "120"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.statusCode, Header: http.Header{}}
+			if tt.header != "" {
+				resp.Header.Set("Retry-After", tt.header)
+			}
+			got, ok := parseRetryAfter(resp)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_httpClientPool(t *testing.T) {
+	pool := &httpClientPool{clients: make(map[httpClientKey]*pooledHTTPClient)}
+	certPool := x509.NewCertPool()
+
+	c1, err := pool.get("", nil, nil)
+	require.NoError(t, err)
+	c2, err := pool.get("", nil, nil)
+	require.NoError(t, err)
+	require.Same(t, c1, c2)
+
+	c3, err := pool.get("", certPool, nil)
+	require.NoError(t, err)
+	require.NotSame(t, c1, c3)
+
+	c4, err := pool.get("http://proxy.example.com", certPool, nil)
+	require.NoError(t, err)
+	require.NotSame(t, c3, c4)
+
+	policy, err := NewEgressPolicy(EgressPolicyOptions{BlockPrivateNetworks: true})
+	require.NoError(t, err)
+	c5, err := pool.get("", nil, policy)
+	require.NoError(t, err)
+	require.NotSame(t, c1, c5)
+	require.Len(t, pool.clients, 4)
+
+	_, err = pool.get("://invalid", nil, nil)
+	require.Error(t, err)
+
+	// Expire the unused clients.
+	for k, c := range pool.clients {
+		if k.proxy != "" {
+			c.lastUsed = time.Now().Add(-2 * httpClientPoolIdleTimeout)
+		}
+	}
+	_, err = pool.get("http://other-proxy.example.com", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, pool.clients, 4)
+	require.NotContains(t, pool.clients, httpClientKey{proxy: "http://proxy.example.com", certPool: certPool})
+}
+
+func TestNewCertPool(t *testing.T) {
+	newCAPEM := func() []byte {
+		ts := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		defer ts.Close()
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	}
+	ca := newCAPEM()
+
+	// The pools parsed from the same PEM bytes are shared, and so are the
+	// HTTP clients using them.
+	p1, err := NewCertPool(ca)
+	require.NoError(t, err)
+	p2, err := NewCertPool(append([]byte(nil), ca...))
+	require.NoError(t, err)
+	require.Same(t, p1, p2)
+
+	pool := &httpClientPool{clients: make(map[httpClientKey]*pooledHTTPClient)}
+	c1, err := pool.get("", p1, nil)
+	require.NoError(t, err)
+	c2, err := pool.get("", p2, nil)
+	require.NoError(t, err)
+	require.Same(t, c1, c2)
+
+	p3, err := NewCertPool(append(ca, ca...))
+	require.NoError(t, err)
+	require.NotSame(t, p1, p3)
+
+	_, err = NewCertPool([]byte("invalid"))
+	require.Error(t, err)
+}
+
 func testEvent() eventv1.Event {
 	return eventv1.Event{
 		InvolvedObject: corev1.ObjectReference{
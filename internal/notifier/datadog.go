@@ -18,7 +18,6 @@ package notifier
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"net/http"
@@ -32,6 +31,8 @@ import (
 )
 
 type DataDog struct {
+	egress
+
 	apiClient *datadog.APIClient
 	eventsApi *datadogV1.EventsApi
 	apiKey    string
@@ -56,37 +57,24 @@ func NewDataDog(address string, proxyUrl string, certPool *x509.CertPool, token
 	conf.Host = baseUrl.Host
 	conf.Scheme = baseUrl.Scheme
 
-	if proxyUrl != "" || certPool != nil {
-		transport := &http.Transport{}
-
-		if proxyUrl != "" {
-			proxy, err := url.Parse(proxyUrl)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse proxy URL %q: %w", proxyUrl, err)
-			}
-
-			transport.Proxy = http.ProxyURL(proxy)
+	d := &DataDog{apiKey: token}
+	transport := d.newHTTPTransport(certPool)
+	if proxyUrl != "" {
+		proxy, err := url.Parse(proxyUrl)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse proxy URL %q: %w", proxyUrl, err)
 		}
 
-		if certPool != nil {
-			transport.TLSClientConfig = &tls.Config{
-				RootCAs: certPool,
-			}
-		}
-
-		conf.HTTPClient = &http.Client{
-			Transport: transport,
-		}
+		transport.Proxy = d.egressProxy(http.ProxyURL(proxy))
 	}
 
-	apiClient := datadog.NewAPIClient(conf)
-	eventsApi := datadogV1.NewEventsApi(apiClient)
+	conf.HTTPClient = &http.Client{
+		Transport: transport,
+	}
 
-	return &DataDog{
-		apiClient: apiClient,
-		eventsApi: eventsApi,
-		apiKey:    token,
-	}, nil
+	d.apiClient = datadog.NewAPIClient(conf)
+	d.eventsApi = datadogV1.NewEventsApi(d.apiClient)
+	return d, nil
 }
 
 func (d *DataDog) Post(ctx context.Context, event eventv1.Event) error {
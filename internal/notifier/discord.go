@@ -28,6 +28,8 @@ import (
 
 // Discord holds the hook URL
 type Discord struct {
+	egress
+
 	URL      string
 	ProxyURL string
 	Username string
@@ -63,6 +65,8 @@ func (s *Discord) Post(ctx context.Context, event eventv1.Event) error {
 		return nil
 	}
 
+	event.Message = resolvedMessage(ctx, event)
+
 	payload := SlackPayload{
 		Username: s.Username,
 	}
@@ -90,7 +94,7 @@ func (s *Discord) Post(ctx context.Context, event eventv1.Event) error {
 
 	payload.Attachments = []SlackAttachment{a}
 
-	err := postMessage(ctx, s.URL, s.ProxyURL, nil, payload)
+	err := postMessage(ctx, s.URL, s.ProxyURL, nil, s.egressPolicy(), payload)
 	if err != nil {
 		return fmt.Errorf("postMessage failed: %w", err)
 	}
@@ -0,0 +1,346 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// ErrEgressDenied is returned when a connection to a provider is denied by
+// the egress policy.
+var ErrEgressDenied = errors.New("denied by the egress policy")
+
+// EgressPolicyOptions configures the addresses the providers can connect to.
+// The denied hosts and networks take precedence over the allowed ones. When
+// allowed hosts or networks are set, the connections to all the other
+// addresses are denied.
+type EgressPolicyOptions struct {
+	// AllowCIDRs are the networks the providers can connect to, including
+	// private networks.
+	AllowCIDRs []string
+	// DenyCIDRs are the networks the providers can't connect to.
+	DenyCIDRs []string
+	// AllowHosts are the host names the providers can connect to, including
+	// the ones resolving to private networks. A leading "*." matches the
+	// subdomains.
+	AllowHosts []string
+	// DenyHosts are the host names the providers can't connect to. A leading
+	// "*." matches the subdomains.
+	DenyHosts []string
+	// BlockPrivateNetworks denies the connections to the private, loopback,
+	// link-local and unspecified addresses.
+	BlockPrivateNetworks bool
+}
+
+// EgressPolicy is the policy enforced on the connections of the providers.
+// A nil *EgressPolicy allows all the connections.
+type EgressPolicy struct {
+	allowCIDRs   []netip.Prefix
+	denyCIDRs    []netip.Prefix
+	allowHosts   []string
+	denyHosts    []string
+	blockPrivate bool
+}
+
+// lookupNetIP resolves the host names of the provider addresses.
+var lookupNetIP = net.DefaultResolver.LookupNetIP
+
+// NewEgressPolicy returns the policy configured by the given options, or nil
+// when it allows all the connections.
+func NewEgressPolicy(opts EgressPolicyOptions) (*EgressPolicy, error) {
+	p := &EgressPolicy{
+		allowHosts:   normalizeHosts(opts.AllowHosts),
+		denyHosts:    normalizeHosts(opts.DenyHosts),
+		blockPrivate: opts.BlockPrivateNetworks,
+	}
+	var err error
+	if p.allowCIDRs, err = parsePrefixes(opts.AllowCIDRs); err != nil {
+		return nil, err
+	}
+	if p.denyCIDRs, err = parsePrefixes(opts.DenyCIDRs); err != nil {
+		return nil, err
+	}
+
+	if len(p.allowCIDRs) == 0 && len(p.denyCIDRs) == 0 &&
+		len(p.allowHosts) == 0 && len(p.denyHosts) == 0 && !p.blockPrivate {
+		return nil, nil
+	}
+	return p, nil
+}
+
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid egress CIDR '%s': %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+func normalizeHosts(hosts []string) []string {
+	var normalized []string
+	for _, host := range hosts {
+		if host = normalizeHost(host); host != "" {
+			normalized = append(normalized, host)
+		}
+	}
+	return normalized
+}
+
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// check returns an error wrapping ErrEgressDenied if the connection to the
+// given host, resolved to the given address, is denied.
+func (p *EgressPolicy) check(host string, addr netip.Addr) error {
+	addr = addr.Unmap()
+	var reason string
+	switch {
+	case matchHost(p.denyHosts, host):
+		reason = "host is denied"
+	case matchPrefix(p.denyCIDRs, addr):
+		reason = "address is denied"
+	case matchHost(p.allowHosts, host) || matchPrefix(p.allowCIDRs, addr):
+		return nil
+	case len(p.allowHosts) > 0 || len(p.allowCIDRs) > 0:
+		reason = "address is not allowed"
+	case p.blockPrivate && isPrivateAddr(addr):
+		reason = "private network address"
+	default:
+		return nil
+	}
+	return fmt.Errorf("connection to '%s' (%s) %w: %s", host, addr, ErrEgressDenied, reason)
+}
+
+// resolve returns the addresses of the given host if the connections to all
+// of them are allowed, or an error wrapping ErrEgressDenied otherwise.
+func (p *EgressPolicy) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	var addrs []netip.Addr
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else if addrs, err = lookupNetIP(ctx, "ip", host); err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		if err := p.check(host, addr); err != nil {
+			return nil, err
+		}
+	}
+	return addrs, nil
+}
+
+// checkHost returns an error wrapping ErrEgressDenied if the connections to
+// the given host are denied, for the connections which aren't made by an
+// egressDialer, e.g. through a proxy or by the clients without a dialer
+// hook. The host is resolved and checked before connecting, and the
+// connections to the hosts which can't be resolved are denied.
+func (p *EgressPolicy) checkHost(ctx context.Context, host string) error {
+	host = normalizeHost(host)
+	if _, err := p.resolve(ctx, host); err != nil {
+		if errors.Is(err, ErrEgressDenied) {
+			return err
+		}
+		return fmt.Errorf("connection to '%s' %w: %w", host, ErrEgressDenied, err)
+	}
+	return nil
+}
+
+func matchHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPrefix(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func isPrivateAddr(addr netip.Addr) bool {
+	return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsUnspecified()
+}
+
+// egress is embedded in the notifiers connecting to the providers, for the
+// Factory to set the egress policy enforced on their connections. The
+// dialers and transports of a notifier read the policy when connecting, as
+// they can be created before the policy is set.
+type egress struct {
+	policy *EgressPolicy
+}
+
+func (e *egress) setEgressPolicy(policy *EgressPolicy) {
+	e.policy = policy
+}
+
+// egressPolicy returns the policy enforced on the connections, nil when all
+// the connections are allowed.
+func (e *egress) egressPolicy() *EgressPolicy {
+	if e == nil {
+		return nil
+	}
+	return e.policy
+}
+
+// egressDialer connects to the addresses allowed by the egress policy. The
+// host names are resolved before being checked, and the connection is made
+// to the checked addresses, so that a host can't be resolved again to a
+// denied address.
+type egressDialer struct {
+	egress *egress
+	dial   func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// newEgressDialer returns an egressDialer enforcing the policy of the given
+// egress, and connecting with the given dial function, or with a default
+// dialer if nil.
+func (e *egress) newEgressDialer(dial func(ctx context.Context, network, address string) (net.Conn, error)) *egressDialer {
+	if dial == nil {
+		dial = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext
+	}
+	return &egressDialer{egress: e, dial: dial}
+}
+
+func (d *egressDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	policy := d.egress.egressPolicy()
+	if policy == nil {
+		return d.dial(ctx, network, address)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	host = normalizeHost(host)
+	addrs, err := policy.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialErr error
+	for _, addr := range addrs {
+		conn, err := d.dial(ctx, network, net.JoinHostPort(addr.Unmap().String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		if dialErr == nil {
+			dialErr = err
+		}
+	}
+	if dialErr == nil {
+		dialErr = fmt.Errorf("no address found for host '%s'", host)
+	}
+	return nil, dialErr
+}
+
+// Dial connects without context, for the clients with a custom dialer
+// interface, e.g. NATS.
+func (d *egressDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// egressProxy wraps the given proxy function of an HTTP transport, for the
+// requests sent through a proxy to be checked against the egress policy, as
+// the dialer of the transport only connects to the proxy.
+func (e *egress) egressProxy(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return nil
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		proxyURL, err := proxy(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		if policy := e.egressPolicy(); policy != nil {
+			if err := policy.checkHost(req.Context(), req.URL.Hostname()); err != nil {
+				return nil, err
+			}
+		}
+		return proxyURL, nil
+	}
+}
+
+// enforceTransport makes the given transport connect to the addresses
+// allowed by the egress policy, and send the requests to the allowed hosts
+// only through its proxy. It must be called after the proxy of the
+// transport is set.
+func (e *egress) enforceTransport(tr *http.Transport) {
+	tr.DialContext = e.newEgressDialer(tr.DialContext).DialContext
+	tr.Proxy = e.egressProxy(tr.Proxy)
+}
+
+// newHTTPTransport returns a copy of the default HTTP transport, keeping its
+// proxy from the environment, timeouts and connection pool settings, which
+// connects to the addresses allowed by the egress policy and trusts the
+// given CA certificates if any.
+func (e *egress) newHTTPTransport(certPool *x509.CertPool) *http.Transport {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	e.enforceTransport(tr)
+	if certPool != nil {
+		if tr.TLSClientConfig == nil {
+			tr.TLSClientConfig = &tls.Config{}
+		}
+		tr.TLSClientConfig.RootCAs = certPool
+	}
+	return tr
+}
+
+// enforceEgressPolicy makes the given client connect to the addresses
+// allowed by the egress policy, without retrying the denied connections.
+// The transport of the client must be an *http.Transport.
+func (e *egress) enforceEgressPolicy(c *retryablehttp.Client) {
+	if tr, ok := c.HTTPClient.Transport.(*http.Transport); ok {
+		e.enforceTransport(tr)
+	}
+	checkRetry := c.CheckRetry
+	c.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		if errors.Is(err, ErrEgressDenied) {
+			return false, err
+		}
+		return checkRetry(ctx, resp, err)
+	}
+}
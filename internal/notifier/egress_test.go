@@ -0,0 +1,299 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"os"
+	"os/exec"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func newEgressPolicy(t *testing.T, opts EgressPolicyOptions) *EgressPolicy {
+	t.Helper()
+	policy, err := NewEgressPolicy(opts)
+	require.NoError(t, err)
+	return policy
+}
+
+func Test_egressPolicy_check(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    EgressPolicyOptions
+		host    string
+		addr    string
+		wantErr string
+	}{
+		{
+			name: "private network allowed by default",
+			opts: EgressPolicyOptions{DenyHosts: []string{"example.com"}},
+			host: "alertmanager.monitoring",
+			addr: "10.0.0.1",
+		},
+		{
+			name:    "private network blocked",
+			opts:    EgressPolicyOptions{BlockPrivateNetworks: true},
+			host:    "alertmanager.monitoring",
+			addr:    "10.0.0.1",
+			wantErr: "private network address",
+		},
+		{
+			name:    "metadata endpoint blocked",
+			opts:    EgressPolicyOptions{BlockPrivateNetworks: true},
+			host:    "169.254.169.254",
+			addr:    "169.254.169.254",
+			wantErr: "private network address",
+		},
+		{
+			name:    "IPv4-mapped loopback blocked",
+			opts:    EgressPolicyOptions{BlockPrivateNetworks: true},
+			host:    "::ffff:127.0.0.1",
+			addr:    "::ffff:127.0.0.1",
+			wantErr: "private network address",
+		},
+		{
+			name: "private network allowed by CIDR",
+			opts: EgressPolicyOptions{BlockPrivateNetworks: true, AllowCIDRs: []string{"10.0.0.0/8"}},
+			host: "alertmanager.monitoring",
+			addr: "10.0.0.1",
+		},
+		{
+			name: "private network allowed by host",
+			opts: EgressPolicyOptions{BlockPrivateNetworks: true, AllowHosts: []string{"*.monitoring"}},
+			host: "alertmanager.monitoring",
+			addr: "10.0.0.1",
+		},
+		{
+			name:    "not allowed",
+			opts:    EgressPolicyOptions{AllowHosts: []string{"hooks.slack.com"}},
+			host:    "example.com",
+			addr:    "93.184.215.14",
+			wantErr: "address is not allowed",
+		},
+		{
+			name:    "denied host",
+			opts:    EgressPolicyOptions{AllowCIDRs: []string{"0.0.0.0/0"}, DenyHosts: []string{"*.example.com"}},
+			host:    "api.example.com",
+			addr:    "93.184.215.14",
+			wantErr: "host is denied",
+		},
+		{
+			name:    "denied CIDR",
+			opts:    EgressPolicyOptions{AllowHosts: []string{"kubernetes.default"}, DenyCIDRs: []string{"10.96.0.1/32"}},
+			host:    "kubernetes.default",
+			addr:    "10.96.0.1",
+			wantErr: "address is denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := newEgressPolicy(t, tt.opts)
+			require.NotNil(t, policy)
+
+			err := policy.check(tt.host, netip.MustParseAddr(tt.addr))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrEgressDenied)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNewEgressPolicy(t *testing.T) {
+	require.Nil(t, newEgressPolicy(t, EgressPolicyOptions{}))
+
+	_, err := NewEgressPolicy(EgressPolicyOptions{DenyCIDRs: []string{"10.0.0.1"}})
+	require.Error(t, err)
+}
+
+func Test_egressDialer(t *testing.T) {
+	lookups := 0
+	lookupNetIP = func(ctx context.Context, network, host string) ([]netip.Addr, error) {
+		lookups++
+		return []netip.Addr{netip.MustParseAddr("127.0.0.1")}, nil
+	}
+	t.Cleanup(func() { lookupNetIP = net.DefaultResolver.LookupNetIP })
+
+	var dialed string
+	e := &egress{}
+	d := e.newEgressDialer(func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = address
+		client, server := net.Pipe()
+		server.Close()
+		return client, nil
+	})
+
+	// The policy is read when connecting.
+	e.setEgressPolicy(newEgressPolicy(t, EgressPolicyOptions{BlockPrivateNetworks: true}))
+	_, err := d.DialContext(context.Background(), "tcp", "internal.example.com:443")
+	require.ErrorIs(t, err, ErrEgressDenied)
+	require.Empty(t, dialed)
+
+	e.setEgressPolicy(newEgressPolicy(t, EgressPolicyOptions{AllowHosts: []string{"internal.example.com"}}))
+	_, err = d.DialContext(context.Background(), "tcp", "internal.example.com:443")
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:443", dialed)
+	require.Equal(t, 2, lookups)
+}
+
+func Test_postMessage_egressDenied(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer ts.Close()
+
+	policy := newEgressPolicy(t, EgressPolicyOptions{BlockPrivateNetworks: true})
+	err := postMessage(context.Background(), ts.URL, "", nil, policy, map[string]string{"status": "success"})
+	require.ErrorIs(t, err, ErrEgressDenied)
+	require.ErrorContains(t, err, "giving up after 1 attempt(s)")
+	require.Zero(t, requests.Load())
+
+	policy = newEgressPolicy(t, EgressPolicyOptions{BlockPrivateNetworks: true, AllowCIDRs: []string{"127.0.0.0/8"}})
+	err = postMessage(context.Background(), ts.URL, "", nil, policy, map[string]string{"status": "success"})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func Test_postMessage_egressDeniedThroughProxy(t *testing.T) {
+	lookupNetIP = func(ctx context.Context, network, host string) ([]netip.Addr, error) {
+		if host == "internal.example.com" {
+			return []netip.Addr{netip.MustParseAddr("10.0.0.1")}, nil
+		}
+		if host == "public.example.com" {
+			return []netip.Addr{netip.MustParseAddr("203.0.113.1")}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	t.Cleanup(func() { lookupNetIP = net.DefaultResolver.LookupNetIP })
+
+	var requests []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Host)
+	}))
+	defer proxy.Close()
+
+	// The proxy is allowed, but the hosts of the requests are checked too.
+	policy := newEgressPolicy(t, EgressPolicyOptions{DenyCIDRs: []string{"10.0.0.0/8"}})
+	for _, address := range []string{"http://internal.example.com", "http://unknown.example.com"} {
+		err := postMessage(context.Background(), address, proxy.URL, nil, policy, map[string]string{"status": "success"})
+		require.ErrorIs(t, err, ErrEgressDenied, address)
+		require.ErrorContains(t, err, "giving up after 1 attempt(s)")
+	}
+	require.Empty(t, requests)
+
+	err := postMessage(context.Background(), "http://public.example.com", proxy.URL, nil, policy, map[string]string{"status": "success"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"public.example.com"}, requests)
+
+	// The transports with a proxy set after their creation are checked too.
+	e := &egress{policy: policy}
+	tr := e.newHTTPTransport(nil)
+	proxyURL, err := url.Parse(proxy.URL)
+	require.NoError(t, err)
+	tr.Proxy = e.egressProxy(http.ProxyURL(proxyURL))
+	_, err = (&http.Client{Transport: tr}).Get("http://internal.example.com")
+	require.ErrorIs(t, err, ErrEgressDenied)
+	require.Len(t, requests, 1)
+}
+
+func TestFactory_egressPolicy(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	defer ts.Close()
+
+	policy := newEgressPolicy(t, EgressPolicyOptions{BlockPrivateNetworks: true})
+	for _, provider := range []string{apiv1.GenericProvider, apiv1.DataDogProvider} {
+		n, err := NewFactory(ts.URL, WithEgressPolicy(policy), WithToken("token")).Notifier(provider)
+		require.NoError(t, err)
+		require.ErrorIs(t, n.Post(context.Background(), eventv1.Event{}), ErrEgressDenied, provider)
+	}
+	require.Zero(t, requests.Load())
+}
+
+func Test_newHTTPTransport(t *testing.T) {
+	certPool := x509.NewCertPool()
+	tr := (&egress{}).newHTTPTransport(certPool)
+	require.Same(t, certPool, tr.TLSClientConfig.RootCAs)
+	require.True(t, tr.ForceAttemptHTTP2)
+	require.Equal(t, 10*time.Second, tr.TLSHandshakeTimeout)
+	require.Equal(t, 90*time.Second, tr.IdleConnTimeout)
+	require.Equal(t, 100, tr.MaxIdleConns)
+	require.NotNil(t, tr.Proxy)
+
+	// The default transport is not modified.
+	if tlsConfig := http.DefaultTransport.(*http.Transport).TLSClientConfig; tlsConfig != nil {
+		require.NotSame(t, certPool, tlsConfig.RootCAs)
+	}
+}
+
+// Test_newHTTPTransport_proxyFromEnvironment runs in a new process, as the
+// proxy environment variables are read once per process.
+func Test_newHTTPTransport_proxyFromEnvironment(t *testing.T) {
+	if os.Getenv("NOTIFIER_TEST_PROXY_FROM_ENVIRONMENT") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^Test_newHTTPTransport_proxyFromEnvironment$")
+		cmd.Env = append(os.Environ(),
+			"NOTIFIER_TEST_PROXY_FROM_ENVIRONMENT=1",
+			"HTTP_PROXY=http://proxy.example:3128",
+			"HTTPS_PROXY=http://secure-proxy.example:3128",
+			"NO_PROXY=internal.example",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return
+	}
+
+	tr := (&egress{}).newHTTPTransport(nil)
+	for _, tt := range []struct {
+		url   string
+		proxy string
+	}{
+		{url: "http://gitlab.example/api", proxy: "http://proxy.example:3128"},
+		{url: "https://api.github.example/repos", proxy: "http://secure-proxy.example:3128"},
+		{url: "https://git.internal.example/api"},
+	} {
+		req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+		require.NoError(t, err)
+		proxy, err := tr.Proxy(req)
+		require.NoError(t, err)
+		if tt.proxy == "" {
+			require.Nil(t, proxy, tt.url)
+			continue
+		}
+		require.NotNil(t, proxy, tt.url)
+		require.Equal(t, tt.proxy, proxy.String())
+	}
+}
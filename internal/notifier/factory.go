@@ -17,6 +17,7 @@ limitations under the License.
 package notifier
 
 import (
+	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 
@@ -32,6 +33,10 @@ var (
 		apiv1.GenericProvider:         genericNotifierFunc,
 		apiv1.GenericHMACProvider:     genericHMACNotifierFunc,
 		apiv1.SlackProvider:           slackNotifierFunc,
+		apiv1.MattermostProvider:      mattermostNotifierFunc,
+		apiv1.NtfyProvider:            ntfyNotifierFunc,
+		apiv1.GotifyProvider:          gotifyNotifierFunc,
+		apiv1.SplunkHECProvider:       splunkHECNotifierFunc,
 		apiv1.DiscordProvider:         discordNotifierFunc,
 		apiv1.RocketProvider:          rocketNotifierFunc,
 		apiv1.MSTeamsProvider:         msteamsNotifierFunc,
@@ -40,15 +45,23 @@ var (
 		apiv1.WebexProvider:           webexNotifierFunc,
 		apiv1.SentryProvider:          sentryNotifierFunc,
 		apiv1.AzureEventHubProvider:   azureEventHubNotifierFunc,
+		apiv1.AWSEventBridgeProvider:  awsEventBridgeNotifierFunc,
 		apiv1.TelegramProvider:        telegramNotifierFunc,
 		apiv1.LarkProvider:            larkNotifierFunc,
 		apiv1.Matrix:                  matrixNotifierFunc,
 		apiv1.OpsgenieProvider:        opsgenieNotifierFunc,
 		apiv1.AlertManagerProvider:    alertmanagerNotifierFunc,
 		apiv1.GrafanaProvider:         grafanaNotifierFunc,
+		apiv1.LokiProvider:            lokiNotifierFunc,
 		apiv1.PagerDutyProvider:       pagerDutyNotifierFunc,
 		apiv1.DataDogProvider:         dataDogNotifierFunc,
 		apiv1.NATSProvider:            natsNotifierFunc,
+		apiv1.KafkaProvider:           kafkaNotifierFunc,
+		apiv1.AMQPProvider:            amqpNotifierFunc,
+		apiv1.MQTTProvider:            mqttNotifierFunc,
+		apiv1.AWSSNSProvider:          awsSNSNotifierFunc,
+		apiv1.AWSSQSProvider:          awsSQSNotifierFunc,
+		apiv1.SMTPProvider:            smtpNotifierFunc,
 		apiv1.GitHubProvider:          gitHubNotifierFunc,
 		apiv1.GitHubDispatchProvider:  gitHubDispatchNotifierFunc,
 		apiv1.GitLabProvider:          gitLabNotifierFunc,
@@ -73,12 +86,15 @@ type notifierOptions struct {
 	Token             string
 	Headers           map[string]string
 	CertPool          *x509.CertPool
+	ClientCertificate *tls.Certificate
 	Password          string
 	CommitStatus      string
 	ProviderName      string
 	ProviderNamespace string
 	SecretData        map[string][]byte
 	TokenCache        *pkgcache.TokenCache
+	EgressPolicy      *EgressPolicy
+	SplunkHECBatcher  *SplunkHECBatcher
 }
 
 type Factory struct {
@@ -130,6 +146,13 @@ func WithCertPool(certPool *x509.CertPool) Option {
 	}
 }
 
+// WithClientCertificate sets the TLS client certificate for the notifier.
+func WithClientCertificate(cert *tls.Certificate) Option {
+	return func(o *notifierOptions) {
+		o.ClientCertificate = cert
+	}
+}
+
 // WithPassword sets the password for the notifier.
 func WithPassword(password string) Option {
 	return func(o *notifierOptions) {
@@ -172,6 +195,22 @@ func WithTokenCache(cache *pkgcache.TokenCache) Option {
 	}
 }
 
+// WithEgressPolicy sets the policy enforced on the connections of the
+// notifier.
+func WithEgressPolicy(policy *EgressPolicy) Option {
+	return func(o *notifierOptions) {
+		o.EgressPolicy = policy
+	}
+}
+
+// WithSplunkHECBatcher sets the batcher shared by the Splunk HEC notifiers
+// with batching enabled.
+func WithSplunkHECBatcher(batcher *SplunkHECBatcher) Option {
+	return func(o *notifierOptions) {
+		o.SplunkHECBatcher = batcher
+	}
+}
+
 // NewFactory creates a new notifier factory with the given URL and optional configurations.
 func NewFactory(url string, opts ...Option) *Factory {
 	options := notifierOptions{
@@ -198,6 +237,9 @@ func (f Factory) Notifier(provider string) (Interface, error) {
 	)
 	if notifier, ok := notifiers[provider]; ok {
 		n, err = notifier(f.notifierOptions)
+		if e, ok := n.(interface{ setEgressPolicy(*EgressPolicy) }); ok && err == nil {
+			e.setEgressPolicy(f.EgressPolicy)
+		}
 	} else {
 		err = fmt.Errorf("provider %s not supported", provider)
 	}
@@ -288,6 +330,63 @@ func natsNotifierFunc(opts notifierOptions) (Interface, error) {
 	return NewNATS(opts.URL, opts.Channel, opts.Username, opts.Password)
 }
 
+func kafkaNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewKafka(opts.URL, opts.Channel, opts.Username, opts.Password, opts.Headers, opts.CertPool, opts.ClientCertificate, opts.SecretData)
+}
+
+func amqpNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewAMQP(opts.URL, opts.Channel, opts.Username, opts.Password, opts.Headers, opts.CertPool, opts.ClientCertificate, opts.SecretData)
+}
+
+func mqttNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewMQTT(opts.URL, opts.Channel, opts.Username, opts.Password, opts.CertPool, opts.ClientCertificate, opts.SecretData)
+}
+
+func awsSNSNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewAWSSNS(opts.URL, opts.ProxyURL, opts.Headers, opts.CertPool, opts.SecretData)
+}
+
+func awsSQSNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewAWSSQS(opts.URL, opts.ProxyURL, opts.Headers, opts.CertPool, opts.SecretData)
+}
+
+func awsEventBridgeNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewAWSEventBridge(opts.URL, opts.Channel, opts.ProxyURL, opts.CertPool, opts.SecretData)
+}
+
+func mattermostNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewMattermost(opts.URL, opts.ProxyURL, opts.Token, opts.CertPool, opts.Username, opts.Channel)
+}
+
+func ntfyNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewNtfy(opts.URL, opts.ProxyURL, opts.CertPool, opts.Channel, opts.Token, opts.Username, opts.Password)
+}
+
+func gotifyNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewGotify(opts.URL, opts.ProxyURL, opts.CertPool, opts.Token)
+}
+
+func splunkHECNotifierFunc(opts notifierOptions) (Interface, error) {
+	s, err := NewSplunkHEC(opts.URL, opts.ProxyURL, opts.CertPool, opts.Token, opts.SecretData)
+	if err != nil {
+		return nil, err
+	}
+	if s.batcher != nil && opts.SplunkHECBatcher != nil {
+		s.batcher = opts.SplunkHECBatcher
+	}
+	s.provider = splunkHECProvider{namespace: opts.ProviderNamespace, name: opts.ProviderName}
+	return s, nil
+}
+
+func lokiNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewLoki(opts.URL, opts.ProxyURL, opts.CertPool, opts.Token, opts.Username, opts.Password,
+		opts.Headers, opts.SecretData)
+}
+
+func smtpNotifierFunc(opts notifierOptions) (Interface, error) {
+	return NewSMTP(opts.URL, opts.Channel, opts.Username, opts.Password, opts.CertPool, opts.SecretData)
+}
+
 func gitHubNotifierFunc(opts notifierOptions) (Interface, error) {
 	if opts.Token == "" && opts.Password != "" {
 		opts.Token = opts.Password
@@ -18,7 +18,6 @@ package notifier
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -34,6 +33,8 @@ import (
 )
 
 type Gitea struct {
+	egress
+
 	BaseURL      string
 	Token        string
 	Owner        string
@@ -69,27 +70,20 @@ func NewGitea(commitStatus string, addr string, token string, certPool *x509.Cer
 		return nil, fmt.Errorf("invalid repository id %q", id)
 	}
 
-	tr := &http.Transport{}
-	if certPool != nil {
-		tr.TLSClientConfig = &tls.Config{
-			RootCAs: certPool,
-		}
-	}
-
-	client, err := gitea.NewClient(host, gitea.SetToken(token), gitea.SetHTTPClient(&http.Client{Transport: tr}))
-	if err != nil {
-		return nil, fmt.Errorf("failed creating Gitea client: %w", err)
-	}
-
-	return &Gitea{
+	g := &Gitea{
 		BaseURL:      host,
 		Token:        token,
 		Owner:        idComponents[0],
 		Repo:         idComponents[1],
 		CommitStatus: commitStatus,
-		Client:       client,
 		Debug:        os.Getenv("NOTIFIER_GITEA_DEBUG") == "true",
-	}, nil
+	}
+	tr := g.newHTTPTransport(certPool)
+	g.Client, err = gitea.NewClient(host, gitea.SetToken(token), gitea.SetHTTPClient(&http.Client{Transport: tr}))
+	if err != nil {
+		return nil, fmt.Errorf("failed creating Gitea client: %w", err)
+	}
+	return g, nil
 }
 
 func (g *Gitea) Post(ctx context.Context, event eventv1.Event) error {
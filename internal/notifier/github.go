@@ -30,6 +30,8 @@ import (
 )
 
 type GitHub struct {
+	egress
+
 	Owner        string
 	Repo         string
 	CommitStatus string
@@ -42,17 +44,16 @@ func NewGitHub(commitStatus string, addr string, token string, certPool *x509.Ce
 		return nil, errors.New("commit status cannot be empty")
 	}
 
-	repoInfo, err := getRepoInfoAndGithubClient(addr, token, certPool, proxyURL, providerName, providerNamespace, secretData, tokenCache)
+	g := &GitHub{CommitStatus: commitStatus}
+	repoInfo, err := getRepoInfoAndGithubClient(addr, token, certPool, proxyURL, providerName, providerNamespace, secretData, tokenCache, &g.egress)
 	if err != nil {
 		return nil, err
 	}
 
-	return &GitHub{
-		Owner:        repoInfo.owner,
-		Repo:         repoInfo.repo,
-		CommitStatus: commitStatus,
-		Client:       repoInfo.client,
-	}, nil
+	g.Owner = repoInfo.owner
+	g.Repo = repoInfo.repo
+	g.Client = repoInfo.client
+	return g, nil
 }
 
 // Post Github commit status
@@ -29,22 +29,24 @@ import (
 )
 
 type GitHubDispatch struct {
+	egress
+
 	Owner  string
 	Repo   string
 	Client *github.Client
 }
 
 func NewGitHubDispatch(addr string, token string, certPool *x509.CertPool, proxyURL string, providerName string, providerNamespace string, secretData map[string][]byte, tokenCache *pkgcache.TokenCache) (*GitHubDispatch, error) {
-	repoInfo, err := getRepoInfoAndGithubClient(addr, token, certPool, proxyURL, providerName, providerNamespace, secretData, tokenCache)
+	g := &GitHubDispatch{}
+	repoInfo, err := getRepoInfoAndGithubClient(addr, token, certPool, proxyURL, providerName, providerNamespace, secretData, tokenCache, &g.egress)
 	if err != nil {
 		return nil, err
 	}
 
-	return &GitHubDispatch{
-		Owner:  repoInfo.owner,
-		Repo:   repoInfo.repo,
-		Client: repoInfo.client,
-	}, nil
+	g.Owner = repoInfo.owner
+	g.Repo = repoInfo.repo
+	g.Client = repoInfo.client
+	return g, nil
 }
 
 // Post GitHub Repository Dispatch webhook
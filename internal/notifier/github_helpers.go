@@ -18,7 +18,6 @@ package notifier
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -71,8 +70,9 @@ func getGitHubAppOptions(providerName, providerNamespace, proxy string, secretDa
 	return githubOpts, nil
 }
 
-// getRepoInfoAndGithubClient gets the github client and repository info used by Github and GithubDispatch providers
-func getRepoInfoAndGithubClient(addr string, token string, certPool *x509.CertPool, proxyURL string, providerName string, providerNamespace string, secretData map[string][]byte, tokenCache *pkgcache.TokenCache) (*repoInfo, error) {
+// getRepoInfoAndGithubClient gets the github client and repository info used by Github and GithubDispatch providers,
+// the client connecting to the addresses allowed by the policy of the given egress.
+func getRepoInfoAndGithubClient(addr string, token string, certPool *x509.CertPool, proxyURL string, providerName string, providerNamespace string, secretData map[string][]byte, tokenCache *pkgcache.TokenCache, e *egress) (*repoInfo, error) {
 	if len(token) == 0 {
 		githubOpts, err := getGitHubAppOptions(providerName, providerNamespace, proxyURL, secretData, tokenCache)
 		if err != nil {
@@ -111,19 +111,15 @@ func getRepoInfoAndGithubClient(addr string, token string, certPool *x509.CertPo
 	}
 
 	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(context.Background(), ts)
+	// The CA certificates are only trusted for GitHub Enterprise.
+	if baseUrl.Host == "github.com" {
+		certPool = nil
+	}
+	hc := &http.Client{Transport: e.newHTTPTransport(certPool)}
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, hc)
+	tc := oauth2.NewClient(ctx, ts)
 	client := gogithub.NewClient(tc)
 	if baseUrl.Host != "github.com" {
-		if certPool != nil {
-			tr := &http.Transport{
-				TLSClientConfig: &tls.Config{
-					RootCAs: certPool,
-				},
-			}
-			hc := &http.Client{Transport: tr}
-			ctx := context.WithValue(context.Background(), oauth2.HTTPClient, hc)
-			tc = oauth2.NewClient(ctx, ts)
-		}
 		client, err = gogithub.NewClient(tc).WithEnterpriseURLs(host, host)
 		if err != nil {
 			return nil, fmt.Errorf("could not create enterprise GitHub client: %v", err)
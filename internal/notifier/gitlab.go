@@ -18,7 +18,6 @@ package notifier
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"errors"
 	"fmt"
@@ -31,6 +30,8 @@ import (
 )
 
 type GitLab struct {
+	egress
+
 	Id           string
 	CommitStatus string
 	Client       *gitlab.Client
@@ -51,28 +52,18 @@ func NewGitLab(commitStatus string, addr string, token string, certPool *x509.Ce
 		return nil, errors.New("commit status cannot be empty")
 	}
 
-	opts := []gitlab.ClientOptionFunc{gitlab.WithBaseURL(host)}
-	if certPool != nil {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs: certPool,
-			},
-		}
-		hc := &http.Client{Transport: tr}
-		opts = append(opts, gitlab.WithHTTPClient(hc))
+	g := &GitLab{
+		Id:           id,
+		CommitStatus: commitStatus,
 	}
-	client, err := gitlab.NewClient(token, opts...)
+	hc := &http.Client{Transport: g.newHTTPTransport(certPool)}
+	opts := []gitlab.ClientOptionFunc{gitlab.WithBaseURL(host), gitlab.WithHTTPClient(hc)}
+	g.Client, err = gitlab.NewClient(token, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	gitlab := &GitLab{
-		Id:           id,
-		CommitStatus: commitStatus,
-		Client:       client,
-	}
-
-	return gitlab, nil
+	return g, nil
 }
 
 // Post GitLab commit status
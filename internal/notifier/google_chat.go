@@ -27,6 +27,8 @@ import (
 
 // Slack holds the hook URL
 type GoogleChat struct {
+	egress
+
 	URL      string
 	ProxyURL string
 	Username string
@@ -92,6 +94,8 @@ func (s *GoogleChat) Post(ctx context.Context, event eventv1.Event) error {
 		return nil
 	}
 
+	event.Message = resolvedMessage(ctx, event)
+
 	// Header
 	objName := fmt.Sprintf("%s/%s.%s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, event.InvolvedObject.Namespace)
 	header := GoogleChatCardHeader{
@@ -142,7 +146,7 @@ func (s *GoogleChat) Post(ctx context.Context, event eventv1.Event) error {
 		Cards: []GoogleChatCard{card},
 	}
 
-	err := postMessage(ctx, s.URL, s.ProxyURL, nil, payload)
+	err := postMessage(ctx, s.URL, s.ProxyURL, nil, s.egressPolicy(), payload)
 	if err != nil {
 		return fmt.Errorf("postMessage failed: %w", err)
 	}
@@ -0,0 +1,104 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	// gotifyPriorityNormal is the Gotify priority of the info events.
+	gotifyPriorityNormal = 5
+	// gotifyPriorityHigh is the Gotify priority of the error events.
+	gotifyPriorityHigh = 8
+	// gotifyMessagePath is the API path creating messages.
+	gotifyMessagePath = "/message"
+)
+
+// Gotify holds the Gotify message URL and application token
+type Gotify struct {
+	egress
+
+	URL      string
+	ProxyURL string
+	Token    string
+	CertPool *x509.CertPool
+}
+
+// GotifyPayload holds the Gotify message
+type GotifyPayload struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Priority int    `json:"priority"`
+}
+
+// NewGotify validates the Gotify server URL and application token and returns
+// a Gotify object.
+func NewGotify(address, proxyURL string, certPool *x509.CertPool, token string) (*Gotify, error) {
+	u, err := url.ParseRequestURI(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Gotify URL %s: '%w'", address, err)
+	}
+
+	if token == "" {
+		return nil, errors.New("empty Gotify application token")
+	}
+
+	u.Path = strings.TrimSuffix(u.Path, "/") + gotifyMessagePath
+	return &Gotify{
+		URL:      u.String(),
+		ProxyURL: proxyURL,
+		Token:    token,
+		CertPool: certPool,
+	}, nil
+}
+
+// Post Gotify message
+func (s *Gotify) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	priority := gotifyPriorityNormal
+	if event.Severity == eventv1.EventSeverityError {
+		priority = gotifyPriorityHigh
+	}
+
+	event.Message = resolvedMessage(ctx, event)
+	payload := GotifyPayload{
+		Title:    eventEmoji(event).char + " " + pushTitle(event),
+		Message:  pushMessage(event),
+		Priority: priority,
+	}
+
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload, func(request *retryablehttp.Request) {
+		request.Header.Set("X-Gotify-Key", s.Token)
+	})
+	if err != nil {
+		return fmt.Errorf("postMessage failed: %w", err)
+	}
+	return nil
+}
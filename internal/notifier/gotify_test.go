@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+func TestGotify_Post(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/gotify/message", r.URL.Path)
+		require.Equal(t, "app-token", r.Header.Get("X-Gotify-Key"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var payload = GotifyPayload{}
+		err = json.Unmarshal(b, &payload)
+		require.NoError(t, err)
+		require.Equal(t, "❌ gitrepository/webapp.gitops-system: Reconciliation Failed", payload.Title)
+		require.Equal(t, "message\n\ntest: metadata", payload.Message)
+		require.Equal(t, gotifyPriorityHigh, payload.Priority)
+	}))
+	defer ts.Close()
+
+	gotify, err := NewGotify(ts.URL+"/gotify/", "", nil, "app-token")
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Severity = eventv1.EventSeverityError
+	event.Reason = "ReconciliationFailed"
+	err = gotify.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
+
+func TestNewGotify_emptyToken(t *testing.T) {
+	_, err := NewGotify("https://gotify.example.com", "", nil, "")
+	require.ErrorContains(t, err, "empty Gotify application token")
+}
+
+func TestGotify_PostUpdate(t *testing.T) {
+	gotify, err := NewGotify("http://localhost", "", nil, "app-token")
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Metadata[eventv1.MetaCommitStatusKey] = eventv1.MetaCommitStatusUpdateValue
+	err = gotify.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
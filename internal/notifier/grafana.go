@@ -28,6 +28,8 @@ import (
 )
 
 type Grafana struct {
+	egress
+
 	URL      string
 	Token    string
 	ProxyURL string
@@ -84,7 +86,7 @@ func (g *Grafana) Post(ctx context.Context, event eventv1.Event) error {
 		Tags: sfields,
 	}
 
-	err := postMessage(ctx, g.URL, g.ProxyURL, g.CertPool, payload, func(request *retryablehttp.Request) {
+	err := postMessage(ctx, g.URL, g.ProxyURL, g.CertPool, g.egressPolicy(), payload, func(request *retryablehttp.Request) {
 		if (g.Username != "" && g.Password != "") && g.Token == "" {
 			request.Header.Add("Authorization", "Basic "+basicAuth(g.Username, g.Password))
 		}
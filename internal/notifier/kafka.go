@@ -0,0 +1,271 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// SASL mechanisms supported by the Kafka notifier.
+const (
+	kafkaSASLPlain       = "PLAIN"
+	kafkaSASLSCRAMSHA256 = "SCRAM-SHA-256"
+	kafkaSASLSCRAMSHA512 = "SCRAM-SHA-512"
+)
+
+// Partition keys of the Kafka records.
+const (
+	// kafkaPartitionKeyObject keys the records by the involved object
+	// reference, so that the events of an object are kept in order.
+	kafkaPartitionKeyObject = "object"
+	// kafkaPartitionKeyUID keys the records by the involved object UID.
+	kafkaPartitionKeyUID = "uid"
+	// kafkaPartitionKeyNone doesn't key the records, which are spread
+	// across the partitions.
+	kafkaPartitionKeyNone = "none"
+)
+
+const (
+	kafkaTLSScheme = "tls://"
+	kafkaClientID  = "notification-controller"
+	// kafkaRecordRetries is the number of times producing a record is
+	// retried, e.g. while the partition leaders are elected, before
+	// reporting the last error.
+	kafkaRecordRetries = 5
+	// kafkaDeliveryTimeout bounds the time spent producing a record,
+	// retries included, when the context has no deadline.
+	kafkaDeliveryTimeout = 15 * time.Second
+)
+
+// Kafka holds the configuration of the Kafka brokers and target topic.
+type Kafka struct {
+	egress
+
+	brokers      []string
+	topic        string
+	partitionKey string
+	headers      []kgo.RecordHeader
+	tlsConfig    *tls.Config
+	sasl         sasl.Mechanism
+}
+
+// NewKafka creates a Kafka notifier producing to the given topic on the
+// given comma-separated brokers. TLS is used when the brokers have the
+// tls:// scheme or when CA or client certificates are given. SASL is used
+// when a username and password are given, with the SASL mechanism and the
+// partition key read from the secret data.
+func NewKafka(address, topic, username, password string, headers map[string]string,
+	certPool *x509.CertPool, clientCert *tls.Certificate, secretData map[string][]byte) (*Kafka, error) {
+	if topic == "" {
+		return nil, errors.New("Kafka topic (channel) cannot be empty")
+	}
+
+	useTLS := certPool != nil || clientCert != nil
+	var brokers []string
+	for _, broker := range strings.Split(address, ",") {
+		broker = strings.TrimSpace(broker)
+		if broker == "" {
+			continue
+		}
+		if b, ok := strings.CutPrefix(broker, kafkaTLSScheme); ok {
+			broker = b
+			useTLS = true
+		}
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			return nil, fmt.Errorf("invalid Kafka broker address '%s': %w", broker, err)
+		}
+		brokers = append(brokers, broker)
+	}
+	if len(brokers) == 0 {
+		return nil, errors.New("Kafka brokers (address) cannot be empty")
+	}
+
+	k := &Kafka{
+		brokers:      brokers,
+		topic:        topic,
+		partitionKey: kafkaPartitionKeyObject,
+	}
+
+	if useTLS {
+		k.tlsConfig = &tls.Config{
+			RootCAs:    certPool,
+			MinVersion: tls.VersionTLS12,
+		}
+		if clientCert != nil {
+			k.tlsConfig.Certificates = []tls.Certificate{*clientCert}
+		}
+	}
+
+	if username != "" && password != "" {
+		mechanism := kafkaSASLSCRAMSHA512
+		if v, ok := secretData["saslMechanism"]; ok {
+			mechanism = strings.ToUpper(strings.TrimSpace(string(v)))
+		}
+		switch mechanism {
+		case kafkaSASLPlain:
+			k.sasl = plain.Auth{User: username, Pass: password}.AsMechanism()
+		case kafkaSASLSCRAMSHA256:
+			k.sasl = scram.Auth{User: username, Pass: password}.AsSha256Mechanism()
+		case kafkaSASLSCRAMSHA512:
+			k.sasl = scram.Auth{User: username, Pass: password}.AsSha512Mechanism()
+		default:
+			return nil, fmt.Errorf("unsupported Kafka SASL mechanism '%s', must be one of %s, %s or %s",
+				mechanism, kafkaSASLPlain, kafkaSASLSCRAMSHA256, kafkaSASLSCRAMSHA512)
+		}
+	}
+
+	if v, ok := secretData["partitionKey"]; ok {
+		k.partitionKey = strings.TrimSpace(string(v))
+		switch k.partitionKey {
+		case kafkaPartitionKeyObject, kafkaPartitionKeyUID, kafkaPartitionKeyNone:
+		default:
+			return nil, fmt.Errorf("unsupported Kafka partition key '%s', must be one of %s, %s or %s",
+				k.partitionKey, kafkaPartitionKeyObject, kafkaPartitionKeyUID, kafkaPartitionKeyNone)
+		}
+	}
+
+	for key, value := range headers {
+		k.headers = append(k.headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+	}
+	slices.SortFunc(k.headers, func(a, b kgo.RecordHeader) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return k, nil
+}
+
+// Post publishes Flux events to a Kafka topic.
+func (k *Kafka) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	eventPayload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error json-marshaling event: %w", err)
+	}
+
+	headers := slices.Clone(k.headers)
+	if event.ReportingController != "" {
+		headers = append(headers, kgo.RecordHeader{Key: "reportingController", Value: []byte(event.ReportingController)})
+	}
+	if event.ReportingInstance != "" {
+		headers = append(headers, kgo.RecordHeader{Key: "reportingInstance", Value: []byte(event.ReportingInstance)})
+	}
+
+	timestamp := event.Timestamp.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	client, err := kgo.NewClient(k.clientOptions()...)
+	if err != nil {
+		return fmt.Errorf("error creating Kafka client: %w", err)
+	}
+	defer client.Close()
+
+	record := &kgo.Record{
+		Topic:     k.topic,
+		Key:       k.recordKey(event),
+		Value:     eventPayload,
+		Headers:   headers,
+		Timestamp: timestamp,
+	}
+	if err := client.ProduceSync(ctx, record).FirstErr(); err != nil {
+		return fmt.Errorf("error publishing event to topic %s: %w", k.topic, err)
+	}
+
+	// debug log
+	log.FromContext(ctx).V(1).Info("Event published to Kafka topic", "topic", k.topic)
+
+	return nil
+}
+
+// clientOptions returns the options of the Kafka client. The records are
+// produced idempotently and assigned to the partitions with the murmur2
+// hash of their key, like the Kafka Java client does.
+func (k *Kafka) clientOptions() []kgo.Opt {
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(k.brokers...),
+		kgo.ClientID(kafkaClientID),
+		kgo.Dialer(k.dial),
+		kgo.RecordDeliveryTimeout(kafkaDeliveryTimeout),
+		kgo.RecordRetries(kafkaRecordRetries),
+	}
+	if k.sasl != nil {
+		opts = append(opts, kgo.SASL(k.sasl))
+	}
+	return opts
+}
+
+// dial connects to a broker through the egress policy, over TLS if
+// configured.
+func (k *Kafka) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	conn, err := k.newEgressDialer(nil).DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if k.tlsConfig == nil {
+		return conn, nil
+	}
+
+	cfg := k.tlsConfig.Clone()
+	if cfg.ServerName == "" {
+		host, _, _ := net.SplitHostPort(address)
+		cfg.ServerName = host
+	}
+	tlsConn := tls.Client(conn, cfg)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	return tlsConn, nil
+}
+
+// recordKey returns the key of the record of the given event, nil if the
+// records are not keyed.
+func (k *Kafka) recordKey(event eventv1.Event) []byte {
+	obj := event.InvolvedObject
+	switch k.partitionKey {
+	case kafkaPartitionKeyUID:
+		if obj.UID != "" {
+			return []byte(obj.UID)
+		}
+		return nil
+	case kafkaPartitionKeyNone:
+		return nil
+	default:
+		return []byte(fmt.Sprintf("%s/%s/%s", obj.Kind, obj.Namespace, obj.Name))
+	}
+}
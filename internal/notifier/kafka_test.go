@@ -0,0 +1,298 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newFakeKafkaCluster starts an in-process Kafka cluster with a topic of
+// three partitions and a SASL superuser.
+func newFakeKafkaCluster(t *testing.T, topic, mechanism, username, password string) *kfake.Cluster {
+	t.Helper()
+	cluster, err := kfake.NewCluster(
+		kfake.NumBrokers(1),
+		kfake.SeedTopics(3, topic),
+		kfake.EnableSASL(),
+		kfake.Superuser(mechanism, username, password),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cluster.Close)
+	return cluster
+}
+
+// consumeKafkaRecords reads the records of the topic of the given notifier,
+// connecting to the brokers with its client options.
+func consumeKafkaRecords(t *testing.T, k *Kafka, count int) []*kgo.Record {
+	t.Helper()
+	client, err := kgo.NewClient(append(k.clientOptions(),
+		kgo.ConsumeTopics(k.topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+	)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var records []*kgo.Record
+	for len(records) < count {
+		fetches := client.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("timed out consuming records: got %d, want %d", len(records), count)
+		}
+		records = append(records, fetches.Records()...)
+	}
+	return records
+}
+
+func TestNewKafka(t *testing.T) {
+	tests := []struct {
+		name       string
+		address    string
+		topic      string
+		username   string
+		password   string
+		secretData map[string][]byte
+		wantErr    string
+		wantTLS    bool
+		wantSASL   string
+	}{
+		{
+			name:    "empty topic",
+			address: "kafka:9092",
+			wantErr: "Kafka topic (channel) cannot be empty",
+		},
+		{
+			name:    "empty brokers",
+			address: " , ",
+			topic:   "events",
+			wantErr: "Kafka brokers (address) cannot be empty",
+		},
+		{
+			name:    "broker without port",
+			address: "kafka",
+			topic:   "events",
+			wantErr: "invalid Kafka broker address 'kafka'",
+		},
+		{
+			name:       "unsupported SASL mechanism",
+			address:    "kafka:9092",
+			topic:      "events",
+			username:   "user",
+			password:   "pass",
+			secretData: map[string][]byte{"saslMechanism": []byte("GSSAPI")},
+			wantErr:    "unsupported Kafka SASL mechanism 'GSSAPI'",
+		},
+		{
+			name:       "unsupported partition key",
+			address:    "kafka:9092",
+			topic:      "events",
+			secretData: map[string][]byte{"partitionKey": []byte("name")},
+			wantErr:    "unsupported Kafka partition key 'name'",
+		},
+		{
+			name:     "TLS and default SASL mechanism",
+			address:  "tls://kafka-0:9093, tls://kafka-1:9093",
+			topic:    "events",
+			username: "user",
+			password: "pass",
+			wantTLS:  true,
+			wantSASL: kafkaSASLSCRAMSHA512,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			k, err := NewKafka(tt.address, tt.topic, tt.username, tt.password, nil, nil, nil, tt.secretData)
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(k.brokers).To(Equal([]string{"kafka-0:9093", "kafka-1:9093"}))
+			g.Expect(k.tlsConfig != nil).To(Equal(tt.wantTLS))
+			g.Expect(k.sasl.Name()).To(Equal(tt.wantSASL))
+		})
+	}
+}
+
+func TestKafka_Post(t *testing.T) {
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Kustomization",
+			Namespace: "flux-system",
+			Name:      "apps",
+			UID:       "7c1d4bd2-6ad1-4c4b-9c8e-3c4b1c5a2f10",
+		},
+		Severity:            eventv1.EventSeverityInfo,
+		Timestamp:           metav1.Now(),
+		Message:             "Applied revision: main/abc",
+		Reason:              "ReconciliationSucceeded",
+		ReportingController: "kustomize-controller",
+	}
+
+	tests := []struct {
+		name       string
+		mechanism  string
+		secretData map[string][]byte
+		wantKey    []byte
+	}{
+		{
+			name:      "SCRAM-SHA-512 with object key",
+			mechanism: kafkaSASLSCRAMSHA512,
+			wantKey:   []byte("Kustomization/flux-system/apps"),
+		},
+		{
+			name:       "SCRAM-SHA-256 with UID key",
+			mechanism:  kafkaSASLSCRAMSHA256,
+			secretData: map[string][]byte{"saslMechanism": []byte("scram-sha-256"), "partitionKey": []byte("uid")},
+			wantKey:    []byte(event.InvolvedObject.UID),
+		},
+		{
+			name:       "PLAIN without key",
+			mechanism:  kafkaSASLPlain,
+			secretData: map[string][]byte{"saslMechanism": []byte("PLAIN"), "partitionKey": []byte("none")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := newFakeKafkaCluster(t, "flux-events", tt.mechanism, "flux", "s3cr3t")
+			k, err := NewKafka(cluster.ListenAddrs()[0], "flux-events", "flux", "s3cr3t",
+				map[string]string{"env": "prod"}, nil, nil, tt.secretData)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			g.Expect(k.Post(ctx, event)).To(Succeed())
+			g.Expect(k.Post(ctx, event)).To(Succeed())
+
+			records := consumeKafkaRecords(t, k, 2)
+			g.Expect(records).To(HaveLen(2))
+			for _, record := range records {
+				g.Expect(record.Topic).To(Equal("flux-events"))
+				g.Expect(record.Key).To(Equal(tt.wantKey))
+				g.Expect(record.Headers).To(Equal([]kgo.RecordHeader{
+					{Key: "env", Value: []byte("prod")},
+					{Key: "reportingController", Value: []byte("kustomize-controller")},
+				}))
+
+				var payload eventv1.Event
+				g.Expect(json.Unmarshal(record.Value, &payload)).To(Succeed())
+				g.Expect(payload.InvolvedObject).To(Equal(event.InvolvedObject))
+				g.Expect(payload.Message).To(Equal(event.Message))
+			}
+			if tt.wantKey != nil {
+				// The events of an object are kept on the same partition.
+				g.Expect(records[0].Partition).To(Equal(records[1].Partition))
+			}
+		})
+	}
+}
+
+func TestKafka_Post_TLS(t *testing.T) {
+	g := NewWithT(t)
+
+	// Reuse the certificate of the test server, which is valid for 127.0.0.1.
+	ts := httptest.NewTLSServer(nil)
+	ts.Close()
+	certPool := x509.NewCertPool()
+	certPool.AddCert(ts.Certificate())
+
+	cluster, err := kfake.NewCluster(
+		kfake.NumBrokers(1),
+		kfake.SeedTopics(1, "flux-events"),
+		kfake.TLS(&tls.Config{Certificates: ts.TLS.Certificates}),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer cluster.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	k, err := NewKafka(kafkaTLSScheme+cluster.ListenAddrs()[0], "flux-events", "", "", nil, certPool, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(k.Post(ctx, eventv1.Event{Message: "over TLS"})).To(Succeed())
+
+	records := consumeKafkaRecords(t, k, 1)
+	g.Expect(records).To(HaveLen(1))
+	g.Expect(string(records[0].Value)).To(ContainSubstring("over TLS"))
+
+	untrusted, err := NewKafka(kafkaTLSScheme+cluster.ListenAddrs()[0], "flux-events", "", "", nil, nil, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = untrusted.Post(ctx, eventv1.Event{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("certificate"))
+}
+
+func TestKafka_Post_authenticationFailed(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newFakeKafkaCluster(t, "flux-events", kafkaSASLPlain, "flux", "s3cr3t")
+	// The fake cluster drops the connection on invalid credentials, while
+	// the Kafka brokers answer with an authentication error.
+	cluster.ControlKey(int16(kmsg.SASLAuthenticate), func(req kmsg.Request) (kmsg.Response, error, bool) {
+		resp := req.(*kmsg.SASLAuthenticateRequest).ResponseKind().(*kmsg.SASLAuthenticateResponse)
+		resp.ErrorCode = kerr.SaslAuthenticationFailed.Code
+		cluster.KeepControl()
+		return resp, nil, true
+	})
+	k, err := NewKafka(cluster.ListenAddrs()[0], "flux-events", "flux", "wrong", nil, nil, nil,
+		map[string][]byte{"saslMechanism": []byte("PLAIN")})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = k.Post(ctx, eventv1.Event{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("SASL_AUTHENTICATION_FAILED"))
+}
+
+func TestKafka_Post_commitStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	k, err := NewKafka("127.0.0.1:1", "flux-events", "", "", nil, nil, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	event := eventv1.Event{Metadata: map[string]string{
+		eventv1.MetaCommitStatusKey: eventv1.MetaCommitStatusUpdateValue,
+	}}
+	g.Expect(k.Post(context.Background(), event)).To(Succeed())
+}
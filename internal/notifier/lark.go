@@ -10,6 +10,8 @@ import (
 )
 
 type Lark struct {
+	egress
+
 	URL string
 }
 
@@ -109,5 +111,5 @@ func (l *Lark) Post(ctx context.Context, event eventv1.Event) error {
 		Card:    card,
 	}
 
-	return postMessage(ctx, l.URL, "", nil, payload)
+	return postMessage(ctx, l.URL, "", nil, l.egressPolicy(), payload)
 }
@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	// lokiPushPath is the Loki push API path, used when the address has no
+	// path.
+	lokiPushPath = "/loki/api/v1/push"
+	// lokiTenantIDHeader is the header of the Loki tenant.
+	lokiTenantIDHeader = "X-Scope-OrgID"
+)
+
+// Loki holds the Loki push URL, tenant and credentials
+type Loki struct {
+	egress
+
+	URL      string
+	ProxyURL string
+	CertPool *x509.CertPool
+	Token    string
+	Username string
+	Password string
+	TenantID string
+	Headers  map[string]string
+}
+
+// LokiPayload holds the streams pushed to Loki
+type LokiPayload struct {
+	Streams []LokiStream `json:"streams"`
+}
+
+// LokiStream holds the labels and the timestamped log lines of a stream
+type LokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// LokiLine holds the content of the log line of an event
+type LokiLine struct {
+	Message  string            `json:"message"`
+	Reason   string            `json:"reason,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewLoki validates the Loki URL and returns a Loki object. The tenant of the
+// multi-tenant Loki deployments is read from the secret data.
+func NewLoki(address, proxyURL string, certPool *x509.CertPool, token, username, password string,
+	headers map[string]string, secretData map[string][]byte) (*Loki, error) {
+	u, err := url.ParseRequestURI(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Loki URL %s: '%w'", address, err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = lokiPushPath
+	}
+
+	return &Loki{
+		URL:      u.String(),
+		ProxyURL: proxyURL,
+		CertPool: certPool,
+		Token:    token,
+		Username: username,
+		Password: password,
+		TenantID: strings.TrimSpace(string(secretData["tenantID"])),
+		Headers:  headers,
+	}, nil
+}
+
+// Post pushes the event as a log line to the stream of its involved object
+func (l *Loki) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	line, err := json.Marshal(LokiLine{
+		Message:  resolvedMessage(ctx, event),
+		Reason:   event.Reason,
+		Metadata: event.Metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling Loki log line failed: %w", err)
+	}
+
+	timestamp := event.Timestamp.Time
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	payload := LokiPayload{
+		Streams: []LokiStream{
+			{
+				Stream: lokiLabels(event),
+				Values: [][2]string{{strconv.FormatInt(timestamp.UnixNano(), 10), string(line)}},
+			},
+		},
+	}
+
+	err = postMessage(ctx, l.URL, l.ProxyURL, l.CertPool, l.egressPolicy(), payload, func(request *retryablehttp.Request) {
+		for key, val := range l.Headers {
+			request.Header.Set(key, val)
+		}
+		if l.TenantID != "" {
+			request.Header.Set(lokiTenantIDHeader, l.TenantID)
+		}
+		switch {
+		case l.Token != "":
+			request.Header.Set("Authorization", "Bearer "+l.Token)
+		case l.Username != "" && l.Password != "":
+			request.Header.Set("Authorization", "Basic "+basicAuth(l.Username, l.Password))
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("postMessage failed: %w", err)
+	}
+	return nil
+}
+
+// lokiLabels returns the stream labels of the given event, skipping the
+// empty values which are not allowed by Loki.
+func lokiLabels(event eventv1.Event) map[string]string {
+	labels := make(map[string]string)
+	for name, value := range map[string]string{
+		"kind":                 strings.ToLower(event.InvolvedObject.Kind),
+		"namespace":            event.InvolvedObject.Namespace,
+		"name":                 event.InvolvedObject.Name,
+		"severity":             event.Severity,
+		"reporting_controller": event.ReportingController,
+	} {
+		if value != "" {
+			labels[name] = value
+		}
+	}
+	return labels
+}
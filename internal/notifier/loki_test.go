@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+func TestLoki_Post(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, lokiPushPath, r.URL.Path)
+		require.Equal(t, "tenant-a", r.Header.Get(lokiTenantIDHeader))
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.Equal(t, "value", r.Header.Get("X-Custom"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var payload = LokiPayload{}
+		err = json.Unmarshal(b, &payload)
+		require.NoError(t, err)
+		require.Len(t, payload.Streams, 1)
+		require.Equal(t, map[string]string{
+			"kind":                 "gitrepository",
+			"namespace":            "gitops-system",
+			"name":                 "webapp",
+			"severity":             "info",
+			"reporting_controller": "source-controller",
+		}, payload.Streams[0].Stream)
+		require.Len(t, payload.Streams[0].Values, 1)
+		require.Equal(t, "1700000000123000000", payload.Streams[0].Values[0][0])
+
+		var line LokiLine
+		err = json.Unmarshal([]byte(payload.Streams[0].Values[0][1]), &line)
+		require.NoError(t, err)
+		require.Equal(t, "message", line.Message)
+		require.Equal(t, "reason", line.Reason)
+		require.Equal(t, map[string]string{"test": "metadata"}, line.Metadata)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	loki, err := NewLoki(ts.URL, "", nil, "token", "", "",
+		map[string]string{"X-Custom": "value"}, map[string][]byte{"tenantID": []byte("tenant-a")})
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Timestamp = metav1.NewTime(time.UnixMilli(1700000000123))
+	err = loki.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
+
+func TestLoki_PostBasicAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/custom/push", r.URL.Path)
+		require.Empty(t, r.Header.Get(lokiTenantIDHeader))
+		require.Equal(t, "Basic "+basicAuth("user", "pass"), r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	loki, err := NewLoki(ts.URL+"/custom/push", "", nil, "", "user", "pass", nil, nil)
+	require.NoError(t, err)
+
+	err = loki.Post(context.TODO(), testEvent())
+	require.NoError(t, err)
+}
+
+func TestLoki_PostUpdate(t *testing.T) {
+	loki, err := NewLoki("http://localhost", "", nil, "", "", "", nil, nil)
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Metadata[eventv1.MetaCommitStatusKey] = eventv1.MetaCommitStatusUpdateValue
+	err = loki.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
@@ -15,6 +15,8 @@ import (
 )
 
 type Matrix struct {
+	egress
+
 	Token    string
 	URL      string
 	RoomId   string
@@ -65,7 +67,7 @@ func (m *Matrix) Post(ctx context.Context, event eventv1.Event) error {
 		MsgType: "m.text",
 	}
 
-	err = postMessage(ctx, fullURL, "", m.CertPool, payload, func(request *retryablehttp.Request) {
+	err = postMessage(ctx, fullURL, "", m.CertPool, m.egressPolicy(), payload, func(request *retryablehttp.Request) {
 		request.Method = http.MethodPut
 		request.Header.Add("Authorization", "Bearer "+m.Token)
 	})
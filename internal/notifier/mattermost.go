@@ -0,0 +1,198 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	// mattermostColorInfo is the attachment color of the info events.
+	mattermostColorInfo = "#2EB886"
+	// mattermostColorError is the attachment color of the error events.
+	mattermostColorError = "#A30200"
+	// mattermostPostsPath is the bot API path creating posts.
+	mattermostPostsPath = "/api/v4/posts"
+)
+
+// Mattermost holds the incoming webhook URL, or the server URL and bot token
+type Mattermost struct {
+	egress
+
+	URL      string
+	ProxyURL string
+	Token    string
+	Username string
+	Channel  string
+	CertPool *x509.CertPool
+}
+
+// MattermostPayload holds the incoming webhook message
+type MattermostPayload struct {
+	Channel     string                 `json:"channel,omitempty"`
+	Username    string                 `json:"username,omitempty"`
+	Text        string                 `json:"text,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments,omitempty"`
+	Props       *MattermostProps       `json:"props,omitempty"`
+}
+
+// MattermostPost holds the bot API post
+type MattermostPost struct {
+	ChannelID string          `json:"channel_id"`
+	Message   string          `json:"message,omitempty"`
+	Props     MattermostProps `json:"props"`
+}
+
+// MattermostProps holds the post properties, with the attachments of the bot
+// API posts
+type MattermostProps struct {
+	Card        string                 `json:"card,omitempty"`
+	Attachments []MattermostAttachment `json:"attachments,omitempty"`
+}
+
+// MattermostAttachment holds the message attachment
+type MattermostAttachment struct {
+	Fallback   string            `json:"fallback"`
+	Color      string            `json:"color"`
+	AuthorName string            `json:"author_name"`
+	Title      string            `json:"title,omitempty"`
+	Text       string            `json:"text"`
+	Fields     []MattermostField `json:"fields,omitempty"`
+	Footer     string            `json:"footer,omitempty"`
+}
+
+// MattermostField holds an attachment field
+type MattermostField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// NewMattermost validates the Mattermost URL and returns a Mattermost object.
+// With a token, the posts are created with the bot API of the server at the
+// given URL, in the channel of the given ID. Otherwise, the URL is an
+// incoming webhook, posting to its default channel or the given channel.
+func NewMattermost(address, proxyURL, token string, certPool *x509.CertPool, username, channel string) (*Mattermost, error) {
+	u, err := url.ParseRequestURI(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Mattermost URL %s: '%w'", address, err)
+	}
+
+	if token != "" {
+		if channel == "" {
+			return nil, errors.New("Mattermost channel ID (channel) cannot be empty with a bot token")
+		}
+		u.Path = strings.TrimSuffix(u.Path, "/") + mattermostPostsPath
+		address = u.String()
+	}
+
+	return &Mattermost{
+		URL:      address,
+		ProxyURL: proxyURL,
+		Token:    token,
+		Username: username,
+		Channel:  channel,
+		CertPool: certPool,
+	}, nil
+}
+
+// Post Mattermost message
+func (s *Mattermost) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	event.Message = resolvedMessage(ctx, event)
+	attachment, card := s.createAttachment(event)
+
+	var payload any
+	if s.Token != "" {
+		payload = MattermostPost{
+			ChannelID: s.Channel,
+			Props: MattermostProps{
+				Card:        card,
+				Attachments: []MattermostAttachment{attachment},
+			},
+		}
+	} else {
+		username := s.Username
+		if username == "" {
+			username = event.ReportingController
+		}
+		payload = MattermostPayload{
+			Channel:     s.Channel,
+			Username:    username,
+			Attachments: []MattermostAttachment{attachment},
+			Props:       &MattermostProps{Card: card},
+		}
+	}
+
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload, func(request *retryablehttp.Request) {
+		if s.Token != "" {
+			request.Header.Add("Authorization", "Bearer "+s.Token)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("postMessage failed: %w", err)
+	}
+	return nil
+}
+
+// createAttachment returns the message attachment of the given event, colored
+// by severity and with the metadata as fields, and the Markdown card shown in
+// the post details.
+func (s *Mattermost) createAttachment(event eventv1.Event) (MattermostAttachment, string) {
+	color := mattermostColorInfo
+	if event.Severity == eventv1.EventSeverityError {
+		color = mattermostColorError
+	}
+
+	objName := fmt.Sprintf("%s/%s.%s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, event.InvolvedObject.Namespace)
+	fields := make([]MattermostField, 0, len(event.Metadata))
+	var card strings.Builder
+	fmt.Fprintf(&card, "#### %s\n\n| Key | Value |\n| --- | --- |\n", objName)
+	fmt.Fprintf(&card, "| severity | %s |\n", event.Severity)
+	if event.Reason != "" {
+		fmt.Fprintf(&card, "| reason | %s |\n", event.Reason)
+	}
+	for _, k := range slices.Sorted(maps.Keys(event.Metadata)) {
+		v := event.Metadata[k]
+		fields = append(fields, MattermostField{Title: k, Value: v, Short: len(v) <= 40})
+		fmt.Fprintf(&card, "| %s | %s |\n", k, strings.ReplaceAll(v, "|", "\\|"))
+	}
+
+	return MattermostAttachment{
+		Fallback:   fmt.Sprintf("%s: %s", objName, event.Message),
+		Color:      color,
+		AuthorName: objName,
+		Title:      strings.Join(splitCamelcase(event.Reason), " "),
+		Text:       event.Message,
+		Fields:     fields,
+		Footer:     event.ReportingController,
+	}, card.String()
+}
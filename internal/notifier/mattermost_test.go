@@ -0,0 +1,100 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+func TestMattermost_PostWebhook(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/hooks/xyz", r.URL.Path)
+		require.Empty(t, r.Header.Get("Authorization"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var payload = MattermostPayload{}
+		err = json.Unmarshal(b, &payload)
+		require.NoError(t, err)
+		require.Equal(t, "town-square", payload.Channel)
+		require.Equal(t, "source-controller", payload.Username)
+		require.Len(t, payload.Attachments, 1)
+		require.Equal(t, "gitrepository/webapp.gitops-system", payload.Attachments[0].AuthorName)
+		require.Equal(t, mattermostColorInfo, payload.Attachments[0].Color)
+		require.Equal(t, []MattermostField{{Title: "test", Value: "metadata", Short: true}}, payload.Attachments[0].Fields)
+		require.Contains(t, payload.Props.Card, "| test | metadata |")
+	}))
+	defer ts.Close()
+
+	mattermost, err := NewMattermost(ts.URL+"/hooks/xyz", "", "", nil, "", "town-square")
+	require.NoError(t, err)
+
+	err = mattermost.Post(context.TODO(), testEvent())
+	require.NoError(t, err)
+}
+
+func TestMattermost_PostBot(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/mattermost/api/v4/posts", r.URL.Path)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var post = MattermostPost{}
+		err = json.Unmarshal(b, &post)
+		require.NoError(t, err)
+		require.Equal(t, "4xp9fdt77pncbef59f4k1qe83o", post.ChannelID)
+		require.Len(t, post.Props.Attachments, 1)
+		require.Equal(t, mattermostColorError, post.Props.Attachments[0].Color)
+		require.Equal(t, "message", post.Props.Attachments[0].Text)
+		require.NotEmpty(t, post.Props.Card)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	mattermost, err := NewMattermost(ts.URL+"/mattermost/", "", "token", nil, "", "4xp9fdt77pncbef59f4k1qe83o")
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Severity = eventv1.EventSeverityError
+	err = mattermost.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
+
+func TestNewMattermost_botWithoutChannel(t *testing.T) {
+	_, err := NewMattermost("https://mattermost.example.com", "", "token", nil, "", "")
+	require.ErrorContains(t, err, "Mattermost channel ID (channel) cannot be empty")
+}
+
+func TestMattermost_PostUpdate(t *testing.T) {
+	mattermost, err := NewMattermost("http://localhost", "", "", nil, "", "test")
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Metadata[eventv1.MetaCommitStatusKey] = eventv1.MetaCommitStatusUpdateValue
+	err = mattermost.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
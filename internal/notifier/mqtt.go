@@ -0,0 +1,235 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// mqttDefaultQoS is the QoS of the messages when none is set in the
+	// Provider secret, at least once delivery.
+	mqttDefaultQoS = 1
+	// mqttDisconnectQuiesce is the time in milliseconds given to the client
+	// to complete the work in progress when disconnecting.
+	mqttDisconnectQuiesce = 250
+)
+
+type (
+	// MQTT holds an MQTT client and target topic prefix.
+	MQTT struct {
+		egress
+
+		topic  string
+		qos    byte
+		retain bool
+		client interface {
+			publish(ctx context.Context, topic string, qos byte, retain bool, eventPayload []byte) (err error)
+		}
+	}
+
+	mqttClient struct {
+		egress    *egress
+		broker    *url.URL
+		username  string
+		password  string
+		tlsConfig *tls.Config
+	}
+)
+
+// NewMQTT creates an MQTT notifier publishing to topics under the given
+// topic prefix, on the broker at the given tcp://, mqtt://, ssl://, tls://
+// or mqtts:// URL. The QoS and retain flag of the messages are read from
+// the secret data.
+func NewMQTT(address, topic, username, password string,
+	certPool *x509.CertPool, clientCert *tls.Certificate, secretData map[string][]byte) (*MQTT, error) {
+	if address == "" {
+		return nil, errors.New("MQTT broker (address) cannot be empty")
+	}
+	topic = strings.TrimSuffix(topic, "/")
+	if topic == "" {
+		return nil, errors.New("MQTT topic (channel) cannot be empty")
+	}
+	if strings.ContainsAny(topic, "+#") {
+		return nil, fmt.Errorf("MQTT topic (channel) '%s' cannot contain wildcards", topic)
+	}
+
+	broker, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT broker address: %w", err)
+	}
+	useTLS := certPool != nil || clientCert != nil
+	defaultPort := "1883"
+	switch broker.Scheme {
+	case "tcp", "mqtt":
+		if useTLS {
+			return nil, fmt.Errorf("MQTT broker (address) must use the ssl, tls or mqtts scheme with TLS certificates")
+		}
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+		defaultPort = "8883"
+	default:
+		return nil, fmt.Errorf("unsupported MQTT broker scheme '%s', must be one of tcp, mqtt, ssl, tls or mqtts", broker.Scheme)
+	}
+	if broker.Hostname() == "" {
+		return nil, errors.New("MQTT broker (address) must have a host")
+	}
+	if broker.Port() == "" {
+		broker.Host = net.JoinHostPort(broker.Hostname(), defaultPort)
+	}
+
+	m := &MQTT{
+		topic: topic,
+		qos:   mqttDefaultQoS,
+	}
+	if v, ok := secretData["qos"]; ok {
+		qos, err := strconv.ParseUint(strings.TrimSpace(string(v)), 10, 8)
+		if err != nil || qos > 2 {
+			return nil, fmt.Errorf("invalid MQTT QoS '%s', must be 0, 1 or 2", string(v))
+		}
+		m.qos = byte(qos)
+	}
+	if v, ok := secretData["retain"]; ok {
+		retain, err := strconv.ParseBool(strings.TrimSpace(string(v)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid MQTT retain flag '%s': %w", string(v), err)
+		}
+		m.retain = retain
+	}
+
+	client := &mqttClient{
+		egress:   &m.egress,
+		broker:   broker,
+		username: username,
+		password: password,
+	}
+	if useTLS {
+		client.tlsConfig = &tls.Config{
+			RootCAs:    certPool,
+			ServerName: broker.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		}
+		if clientCert != nil {
+			client.tlsConfig.Certificates = []tls.Certificate{*clientCert}
+		}
+	}
+	m.client = client
+
+	return m, nil
+}
+
+// Post publishes Flux events to the MQTT topic of the involved object.
+func (m *MQTT) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	eventPayload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("error json-marshaling event: %w", err)
+	}
+
+	topic := m.objectTopic(event)
+	err = m.client.publish(ctx, topic, m.qos, m.retain, eventPayload)
+	if err != nil {
+		return fmt.Errorf("error publishing event to topic %s: %w", topic, err)
+	}
+
+	// debug log
+	log.FromContext(ctx).V(1).Info("Event published to MQTT topic", "topic", topic)
+
+	return nil
+}
+
+// objectTopic returns the topic of the involved object of the given event,
+// in the <topic>/<namespace>/<kind>/<name> format.
+func (m *MQTT) objectTopic(event eventv1.Event) string {
+	obj := event.InvolvedObject
+	return strings.Join([]string{m.topic, obj.Namespace, obj.Kind, obj.Name}, "/")
+}
+
+func (m *mqttClient) publish(ctx context.Context, topic string, qos byte, retain bool, eventPayload []byte) (err error) {
+	clientID := make([]byte, 8)
+	if _, err := rand.Read(clientID); err != nil {
+		return fmt.Errorf("error generating client ID: %w", err)
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(m.broker.String()).
+		SetClientID("notification-controller-" + hex.EncodeToString(clientID)).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectRetry(false).
+		SetCustomOpenConnectionFn(func(uri *url.URL, _ mqtt.ClientOptions) (net.Conn, error) {
+			conn, err := m.egress.newEgressDialer(nil).DialContext(ctx, "tcp", uri.Host)
+			if err != nil || m.tlsConfig == nil {
+				return conn, err
+			}
+			tlsConn := tls.Client(conn, m.tlsConfig)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return tlsConn, nil
+		})
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.SetConnectTimeout(time.Until(deadline))
+	}
+	if m.username != "" && m.password != "" {
+		opts.SetUsername(m.username).SetPassword(m.password)
+	}
+
+	client := mqtt.NewClient(opts)
+	if err := waitMQTTToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("error connecting to broker: %w", err)
+	}
+	defer client.Disconnect(mqttDisconnectQuiesce)
+
+	if err := waitMQTTToken(ctx, client.Publish(topic, qos, retain, eventPayload)); err != nil {
+		return fmt.Errorf("error publishing message to broker: %w", err)
+	}
+
+	return nil
+}
+
+// waitMQTTToken waits for the completion of the given token, or for the
+// context to be done.
+func waitMQTTToken(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
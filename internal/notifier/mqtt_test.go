@@ -0,0 +1,287 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// fakeMQTTBroker is an in-process stand-in for an MQTT broker, accepting
+// the connections with the given credentials and recording the published
+// messages.
+type fakeMQTTBroker struct {
+	listener net.Listener
+	username string
+	password string
+
+	mu       sync.Mutex
+	messages []*packets.PublishPacket
+}
+
+func newFakeMQTTBroker(t *testing.T, username, password string) *fakeMQTTBroker {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeMQTTBroker{
+		listener: listener,
+		username: username,
+		password: password,
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeMQTTBroker) published() []*packets.PublishPacket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*packets.PublishPacket(nil), b.messages...)
+}
+
+func (b *fakeMQTTBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		packet, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+
+		var resp packets.ControlPacket
+		switch p := packet.(type) {
+		case *packets.ConnectPacket:
+			connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
+			if p.Username != b.username || string(p.Password) != b.password {
+				connack.ReturnCode = packets.ErrRefusedBadUsernameOrPassword
+			}
+			resp = connack
+		case *packets.PublishPacket:
+			b.mu.Lock()
+			b.messages = append(b.messages, p)
+			b.mu.Unlock()
+			switch p.Qos {
+			case 1:
+				puback := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				puback.MessageID = p.MessageID
+				resp = puback
+			case 2:
+				pubrec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				pubrec.MessageID = p.MessageID
+				resp = pubrec
+			}
+		case *packets.PubrelPacket:
+			pubcomp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			pubcomp.MessageID = p.MessageID
+			resp = pubcomp
+		case *packets.PingreqPacket:
+			resp = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			return
+		}
+		if resp != nil {
+			if err := resp.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestNewMQTT(t *testing.T) {
+	tests := []struct {
+		name           string
+		address        string
+		topic          string
+		certPool       *x509.CertPool
+		secretData     map[string][]byte
+		expectedErr    string
+		expectedBroker string
+		expectedTopic  string
+		expectedQoS    byte
+		expectedRetain bool
+		expectedTLS    bool
+	}{
+		{
+			name:        "empty address is not allowed",
+			topic:       "flux",
+			expectedErr: "MQTT broker (address) cannot be empty",
+		},
+		{
+			name:        "empty topic is not allowed",
+			address:     "tcp://mosquitto:1883",
+			expectedErr: "MQTT topic (channel) cannot be empty",
+		},
+		{
+			name:        "topic wildcards are not allowed",
+			address:     "tcp://mosquitto:1883",
+			topic:       "flux/#",
+			expectedErr: "cannot contain wildcards",
+		},
+		{
+			name:        "websocket scheme is not supported",
+			address:     "ws://mosquitto:8080",
+			topic:       "flux",
+			expectedErr: "unsupported MQTT broker scheme 'ws'",
+		},
+		{
+			name:        "certificates require a TLS scheme",
+			address:     "tcp://mosquitto:1883",
+			topic:       "flux",
+			certPool:    x509.NewCertPool(),
+			expectedErr: "must use the ssl, tls or mqtts scheme",
+		},
+		{
+			name:        "invalid QoS",
+			address:     "tcp://mosquitto:1883",
+			topic:       "flux",
+			secretData:  map[string][]byte{"qos": []byte("3")},
+			expectedErr: "invalid MQTT QoS '3'",
+		},
+		{
+			name:        "invalid retain flag",
+			address:     "tcp://mosquitto:1883",
+			topic:       "flux",
+			secretData:  map[string][]byte{"retain": []byte("maybe")},
+			expectedErr: "invalid MQTT retain flag 'maybe'",
+		},
+		{
+			name:           "default port and options",
+			address:        "mqtt://mosquitto",
+			topic:          "flux/",
+			expectedBroker: "mqtt://mosquitto:1883",
+			expectedTopic:  "flux",
+			expectedQoS:    mqttDefaultQoS,
+		},
+		{
+			name:           "TLS with QoS and retain",
+			address:        "mqtts://mosquitto",
+			topic:          "edge/cluster-a",
+			secretData:     map[string][]byte{"qos": []byte("2"), "retain": []byte("true")},
+			expectedBroker: "mqtts://mosquitto:8883",
+			expectedTopic:  "edge/cluster-a",
+			expectedQoS:    2,
+			expectedRetain: true,
+			expectedTLS:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewMQTT(tt.address, tt.topic, "user", "pass", tt.certPool, nil, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				g.Expect(provider).To(BeNil())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(provider.topic).To(Equal(tt.expectedTopic))
+			g.Expect(provider.qos).To(Equal(tt.expectedQoS))
+			g.Expect(provider.retain).To(Equal(tt.expectedRetain))
+
+			client := provider.client.(*mqttClient)
+			g.Expect(client.broker.String()).To(Equal(tt.expectedBroker))
+			g.Expect(client.tlsConfig != nil).To(Equal(tt.expectedTLS))
+		})
+	}
+}
+
+func TestMQTTPost(t *testing.T) {
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "HelmRelease",
+			Namespace: "apps",
+			Name:      "podinfo",
+		},
+		Severity: eventv1.EventSeverityInfo,
+		Reason:   "UpgradeSucceeded",
+		Message:  "Helm upgrade succeeded",
+	}
+
+	for _, qos := range []string{"0", "1", "2"} {
+		t.Run("qos "+qos, func(t *testing.T) {
+			g := NewWithT(t)
+
+			broker := newFakeMQTTBroker(t, "flux", "s3cr3t")
+			provider, err := NewMQTT("tcp://"+broker.listener.Addr().String(), "edge/cluster-a", "flux", "s3cr3t",
+				nil, nil, map[string][]byte{"qos": []byte(qos), "retain": []byte("true")})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			g.Expect(provider.Post(ctx, event)).To(Succeed())
+
+			g.Eventually(broker.published).Should(HaveLen(1))
+			msg := broker.published()[0]
+			g.Expect(msg.TopicName).To(Equal("edge/cluster-a/apps/HelmRelease/podinfo"))
+			g.Expect(msg.Qos).To(Equal(provider.qos))
+			g.Expect(msg.Retain).To(BeTrue())
+
+			var payload eventv1.Event
+			g.Expect(json.Unmarshal(msg.Payload, &payload)).To(Succeed())
+			g.Expect(payload.InvolvedObject).To(Equal(event.InvolvedObject))
+			g.Expect(payload.Message).To(Equal(event.Message))
+		})
+	}
+}
+
+func TestMQTTPost_badCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	broker := newFakeMQTTBroker(t, "flux", "s3cr3t")
+	provider, err := NewMQTT("tcp://"+broker.listener.Addr().String(), "flux", "flux", "wrong", nil, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = provider.Post(ctx, eventv1.Event{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("error connecting to broker"))
+	g.Expect(broker.published()).To(BeEmpty())
+}
+
+func TestMQTTPost_commitStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	provider, err := NewMQTT("tcp://127.0.0.1:1", "flux", "", "", nil, nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	event := eventv1.Event{Metadata: map[string]string{
+		eventv1.MetaCommitStatusKey: eventv1.MetaCommitStatusUpdateValue,
+	}}
+	g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+}
@@ -30,6 +30,8 @@ import (
 type (
 	// NATS holds a NATS client and target subject.
 	NATS struct {
+		egress
+
 		subject string
 		client  interface {
 			publish(ctx context.Context, subject string, eventPayload []byte) (err error)
@@ -37,6 +39,7 @@ type (
 	}
 
 	natsClient struct {
+		egress   *egress
 		server   string
 		username string
 		password string
@@ -50,14 +53,14 @@ func NewNATS(server string, subject string, username string, password string) (*
 	if subject == "" {
 		return nil, errors.New("NATS subject (channel) cannot be empty")
 	}
-	return &NATS{
-		subject: subject,
-		client: &natsClient{
-			server:   server,
-			username: username,
-			password: password,
-		},
-	}, nil
+	n := &NATS{subject: subject}
+	n.client = &natsClient{
+		egress:   &n.egress,
+		server:   server,
+		username: username,
+		password: password,
+	}
+	return n, nil
 }
 
 // Post posts Flux events to a NATS subject.
@@ -84,7 +87,10 @@ func (n *NATS) Post(ctx context.Context, event eventv1.Event) error {
 }
 
 func (n *natsClient) publish(ctx context.Context, subject string, eventPayload []byte) (err error) {
-	opts := []nats.Option{nats.Name("NATS Provider Publisher")}
+	opts := []nats.Option{
+		nats.Name("NATS Provider Publisher"),
+		nats.SetCustomDialer(n.egress.newEgressDialer(nil)),
+	}
 	if n.username != "" && n.password != "" {
 		opts = append(opts, nats.UserInfo(n.username, n.password))
 	}
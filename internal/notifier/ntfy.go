@@ -0,0 +1,149 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	// ntfyPriorityDefault is the ntfy priority of the info events.
+	ntfyPriorityDefault = 3
+	// ntfyPriorityHigh is the ntfy priority of the error events.
+	ntfyPriorityHigh = 4
+)
+
+// Ntfy holds the ntfy server URL, topic and credentials
+type Ntfy struct {
+	egress
+
+	URL      string
+	ProxyURL string
+	Topic    string
+	Token    string
+	Username string
+	Password string
+	CertPool *x509.CertPool
+}
+
+// NtfyPayload holds the ntfy JSON message
+type NtfyPayload struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title,omitempty"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// NewNtfy validates the ntfy URL and returns a Ntfy object. The topic is the
+// given channel, or else the last path segment of the URL, e.g. flux for
+// https://ntfy.sh/flux. The messages are authenticated with the access token
+// when given, or else with the username and password.
+func NewNtfy(address, proxyURL string, certPool *x509.CertPool, topic, token, username, password string) (*Ntfy, error) {
+	u, err := url.ParseRequestURI(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ntfy URL %s: '%w'", address, err)
+	}
+
+	if topic == "" {
+		u.Path, topic = path.Split(strings.TrimSuffix(u.Path, "/"))
+	}
+	if topic == "" {
+		return nil, errors.New("ntfy topic (channel) cannot be empty")
+	}
+
+	return &Ntfy{
+		URL:      u.String(),
+		ProxyURL: proxyURL,
+		Topic:    topic,
+		Token:    token,
+		Username: username,
+		Password: password,
+		CertPool: certPool,
+	}, nil
+}
+
+// Post ntfy message
+func (s *Ntfy) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	priority := ntfyPriorityDefault
+	if event.Severity == eventv1.EventSeverityError {
+		priority = ntfyPriorityHigh
+	}
+
+	event.Message = resolvedMessage(ctx, event)
+	payload := NtfyPayload{
+		Topic:    s.Topic,
+		Title:    pushTitle(event),
+		Message:  pushMessage(event),
+		Priority: priority,
+		Tags:     []string{eventEmoji(event).tag},
+	}
+
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload, func(request *retryablehttp.Request) {
+		switch {
+		case s.Token != "":
+			request.Header.Set("Authorization", "Bearer "+s.Token)
+		case s.Username != "" && s.Password != "":
+			request.Header.Set("Authorization", "Basic "+basicAuth(s.Username, s.Password))
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("postMessage failed: %w", err)
+	}
+	return nil
+}
+
+// pushTitle returns the push notification title of the given event, with the
+// involved object and the reason.
+func pushTitle(event eventv1.Event) string {
+	title := fmt.Sprintf("%s/%s.%s", strings.ToLower(event.InvolvedObject.Kind),
+		event.InvolvedObject.Name, event.InvolvedObject.Namespace)
+	if event.Reason != "" {
+		title = fmt.Sprintf("%s: %s", title, strings.Join(splitCamelcase(event.Reason), " "))
+	}
+	return title
+}
+
+// pushMessage returns the push notification body of the given event, with
+// the message followed by the sorted metadata.
+func pushMessage(event eventv1.Event) string {
+	var msg strings.Builder
+	msg.WriteString(event.Message)
+	if len(event.Metadata) > 0 {
+		msg.WriteString("\n")
+		for _, k := range slices.Sorted(maps.Keys(event.Metadata)) {
+			fmt.Fprintf(&msg, "\n%s: %s", k, event.Metadata[k])
+		}
+	}
+	return msg.String()
+}
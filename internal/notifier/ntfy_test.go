@@ -0,0 +1,97 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+func TestNtfy_Post(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/", r.URL.Path)
+		require.Equal(t, "Bearer tk_token", r.Header.Get("Authorization"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var payload = NtfyPayload{}
+		err = json.Unmarshal(b, &payload)
+		require.NoError(t, err)
+		require.Equal(t, "flux", payload.Topic)
+		require.Equal(t, "gitrepository/webapp.gitops-system: reason", payload.Title)
+		require.Equal(t, "message\n\ntest: metadata", payload.Message)
+		require.Equal(t, ntfyPriorityDefault, payload.Priority)
+		require.Equal(t, []string{"information_source"}, payload.Tags)
+	}))
+	defer ts.Close()
+
+	ntfy, err := NewNtfy(ts.URL+"/flux", "", nil, "", "tk_token", "", "")
+	require.NoError(t, err)
+
+	err = ntfy.Post(context.TODO(), testEvent())
+	require.NoError(t, err)
+}
+
+func TestNtfy_PostError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/ntfy/", r.URL.Path)
+		require.Equal(t, "Basic "+basicAuth("user", "pass"), r.Header.Get("Authorization"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var payload = NtfyPayload{}
+		err = json.Unmarshal(b, &payload)
+		require.NoError(t, err)
+		require.Equal(t, "alerts", payload.Topic)
+		require.Equal(t, "gitrepository/webapp.gitops-system: Health Check Failed", payload.Title)
+		require.Equal(t, ntfyPriorityHigh, payload.Priority)
+		require.Equal(t, []string{"broken_heart"}, payload.Tags)
+	}))
+	defer ts.Close()
+
+	ntfy, err := NewNtfy(ts.URL+"/ntfy/", "", nil, "alerts", "", "user", "pass")
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Severity = eventv1.EventSeverityError
+	event.Reason = "HealthCheckFailed"
+	err = ntfy.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
+
+func TestNewNtfy_emptyTopic(t *testing.T) {
+	_, err := NewNtfy("https://ntfy.sh/", "", nil, "", "", "", "")
+	require.ErrorContains(t, err, "ntfy topic (channel) cannot be empty")
+}
+
+func TestNtfy_PostUpdate(t *testing.T) {
+	ntfy, err := NewNtfy("http://localhost/flux", "", nil, "", "", "", "")
+	require.NoError(t, err)
+
+	event := testEvent()
+	event.Metadata[eventv1.MetaCommitStatusKey] = eventv1.MetaCommitStatusUpdateValue
+	err = ntfy.Post(context.TODO(), event)
+	require.NoError(t, err)
+}
@@ -22,12 +22,15 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
 type Opsgenie struct {
+	egress
+
 	URL      string
 	ProxyURL string
 	CertPool *x509.CertPool
@@ -36,10 +39,17 @@ type Opsgenie struct {
 
 type OpsgenieAlert struct {
 	Message     string            `json:"message"`
+	Alias       string            `json:"alias,omitempty"`
 	Description string            `json:"description"`
 	Details     map[string]string `json:"details"`
 }
 
+// OpsgenieCloseAlert holds the payload for closing an alert.
+type OpsgenieCloseAlert struct {
+	Source string `json:"source,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
 func NewOpsgenie(hookURL string, proxyURL string, certPool *x509.CertPool, token string) (*Opsgenie, error) {
 	_, err := url.ParseRequestURI(hookURL)
 	if err != nil {
@@ -78,12 +88,41 @@ func (s *Opsgenie) Post(ctx context.Context, event eventv1.Event) error {
 		Details:     details,
 	}
 
-	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, payload, func(req *retryablehttp.Request) {
-		req.Header.Set("Authorization", "GenieKey "+s.ApiKey)
-	})
+	// Deduplicate the alerts of a failing object, so they can be closed
+	// once the object recovers.
+	if event.Severity == eventv1.EventSeverityError {
+		payload.Alias = opsgenieAlias(event)
+	}
 
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload, s.authorize)
 	if err != nil {
 		return fmt.Errorf("postMessage failed: %w", err)
 	}
+
+	if r, ok := ResolutionFromContext(ctx); ok {
+		closeURL, err := url.JoinPath(s.URL, opsgenieAlias(r.FailedEvent), "close")
+		if err != nil {
+			return fmt.Errorf("invalid Opsgenie close alert URL: %w", err)
+		}
+		closePayload := OpsgenieCloseAlert{
+			Source: "Flux " + event.ReportingController,
+			Note:   resolvedMessage(ctx, event),
+		}
+		err = postMessage(ctx, closeURL+"?identifierType=alias", s.ProxyURL, s.CertPool, s.egressPolicy(), closePayload, s.authorize)
+		if err != nil {
+			return fmt.Errorf("postMessage failed to close alert: %w", err)
+		}
+	}
 	return nil
 }
+
+func (s *Opsgenie) authorize(req *retryablehttp.Request) {
+	req.Header.Set("Authorization", "GenieKey "+s.ApiKey)
+}
+
+// opsgenieAlias returns the alias identifying the alerts of the involved
+// object of the given event.
+func opsgenieAlias(event eventv1.Event) string {
+	return strings.ToLower(fmt.Sprintf("flux:%s:%s:%s", event.InvolvedObject.Kind,
+		event.InvolvedObject.Namespace, event.InvolvedObject.Name))
+}
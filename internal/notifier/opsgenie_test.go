@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/fluxcd/pkg/apis/event/v1beta1"
 	"github.com/stretchr/testify/require"
@@ -68,3 +69,45 @@ func TestOpsgenie_Post(t *testing.T) {
 		})
 	}
 }
+
+func TestOpsgenie_PostResolution(t *testing.T) {
+	var paths []string
+	var alert OpsgenieAlert
+	var closeAlert OpsgenieCloseAlert
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.String())
+		require.Equal(t, "GenieKey token", r.Header.Get("Authorization"))
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if r.URL.Path == "/v2/alerts" {
+			alert = OpsgenieAlert{}
+			require.NoError(t, json.Unmarshal(b, &alert))
+		} else {
+			require.NoError(t, json.Unmarshal(b, &closeAlert))
+		}
+	}))
+	defer ts.Close()
+
+	opsgenie, err := NewOpsgenie(ts.URL+"/v2/alerts", "", nil, "token")
+	require.NoError(t, err)
+
+	failed := testEvent()
+	failed.Severity = v1beta1.EventSeverityError
+	require.NoError(t, opsgenie.Post(context.TODO(), failed))
+	require.Equal(t, "flux:gitrepository:gitops-system:webapp", alert.Alias)
+
+	event := testEvent()
+	ctx := WithResolution(context.TODO(), Resolution{
+		FailedEvent:  failed,
+		FailingSince: event.Timestamp.Add(-time.Minute),
+	})
+	require.NoError(t, opsgenie.Post(ctx, event))
+	require.Equal(t, []string{
+		"/v2/alerts",
+		"/v2/alerts",
+		"/v2/alerts/flux:gitrepository:gitops-system:webapp/close?identifierType=alias",
+	}, paths)
+	require.Empty(t, alert.Alias)
+	require.Equal(t, "Flux source-controller", closeAlert.Source)
+	require.Equal(t, "Resolved after 1m0s: message", closeAlert.Note)
+}
@@ -30,6 +30,8 @@ import (
 )
 
 type PagerDuty struct {
+	egress
+
 	Endpoint   string
 	RoutingKey string
 	ProxyURL   string
@@ -54,15 +56,23 @@ func (p *PagerDuty) Post(ctx context.Context, event eventv1.Event) error {
 	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) || event.HasReason(meta.ProgressingReason) {
 		return nil
 	}
-	e := toPagerDutyV2Event(event, p.RoutingKey)
-	err := postMessage(ctx, p.Endpoint+"/v2/enqueue", p.ProxyURL, p.CertPool, e)
-	if err != nil {
-		return fmt.Errorf("failed sending event: %w", err)
+	// Trigger an incident for errors and resolve it only once the
+	// involved object recovers from the failure. The incident is resolved
+	// as well when the state of the object is unknown, as the failure may
+	// have been reported before a restart of the controller.
+	_, resolved := ResolutionFromContext(ctx)
+	resolved = resolved || IsUntrackedRecovery(ctx)
+	if resolved || event.Severity == eventv1.EventSeverityError {
+		e := toPagerDutyV2Event(event, p.RoutingKey)
+		err := postMessage(ctx, p.Endpoint+"/v2/enqueue", p.ProxyURL, p.CertPool, p.egressPolicy(), e)
+		if err != nil {
+			return fmt.Errorf("failed sending event: %w", err)
+		}
 	}
 	// Send a change event for info events
 	if event.Severity == eventv1.EventSeverityInfo {
 		ce := toPagerDutyChangeEvent(event, p.RoutingKey)
-		err = postMessage(ctx, p.Endpoint+"/v2/change/enqueue", p.ProxyURL, p.CertPool, ce)
+		err := postMessage(ctx, p.Endpoint+"/v2/change/enqueue", p.ProxyURL, p.CertPool, p.egressPolicy(), ce)
 		if err != nil {
 			return fmt.Errorf("failed sending change event: %w", err)
 		}
@@ -72,7 +82,7 @@ func (p *PagerDuty) Post(ctx context.Context, event eventv1.Event) error {
 
 func toPagerDutyV2Event(event eventv1.Event, routingKey string) pagerduty.V2Event {
 	name, desc := formatNameAndDescription(event)
-	// Send resolve just in case an existing incident is open
+	// Resolve the incident opened for the involved object
 	e := pagerduty.V2Event{
 		RoutingKey: routingKey,
 		Action:     "resolve",
@@ -70,6 +70,72 @@ func TestPagerDutyPost(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPagerDutyPostResolution(t *testing.T) {
+	tests := []struct {
+		name       string
+		severity   string
+		resolution bool
+		untracked  bool
+		wantAction string
+	}{
+		{
+			name:       "error event triggers incident",
+			severity:   eventv1.EventSeverityError,
+			wantAction: "trigger",
+		},
+		{
+			name:     "info event does not resolve incident",
+			severity: eventv1.EventSeverityInfo,
+		},
+		{
+			name:       "info event resolving failure resolves incident",
+			severity:   eventv1.EventSeverityInfo,
+			resolution: true,
+			wantAction: "resolve",
+		},
+		{
+			name:       "info event of untracked object resolves incident",
+			severity:   eventv1.EventSeverityInfo,
+			untracked:  true,
+			wantAction: "resolve",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var actions []string
+			mux := http.NewServeMux()
+			mux.HandleFunc("/v2/enqueue", func(w http.ResponseWriter, r *http.Request) {
+				var payload pagerduty.V2Event
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				actions = append(actions, payload.Action)
+			})
+			mux.HandleFunc("/v2/change/enqueue", func(w http.ResponseWriter, r *http.Request) {})
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+
+			pd, err := NewPagerDuty(ts.URL, "", nil, "token")
+			require.NoError(t, err)
+
+			ctx := context.TODO()
+			if tt.resolution {
+				ctx = WithResolution(ctx, Resolution{FailedEvent: testEvent()})
+			}
+			if tt.untracked {
+				ctx = WithUntrackedRecovery(ctx)
+			}
+			event := testEvent()
+			event.Severity = tt.severity
+			require.NoError(t, pd.Post(ctx, event))
+
+			if tt.wantAction == "" {
+				assert.Empty(t, actions)
+			} else {
+				assert.Equal(t, []string{tt.wantAction}, actions)
+			}
+		})
+	}
+}
+
 func TestToPagerDutyV2Event(t *testing.T) {
 	// Construct test event
 	tests := []struct {
@@ -0,0 +1,86 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+// Resolution describes the recovery of an involved object from a previously
+// reported failure. It is attached to the context passed to Interface.Post
+// when the event being posted is the first successful event observed for
+// the object after one or more error events.
+type Resolution struct {
+	// FailedEvent is the last error event dispatched for the involved object.
+	FailedEvent eventv1.Event
+
+	// FailingSince is the time of the first error event observed for the
+	// involved object.
+	FailingSince time.Time
+}
+
+type resolutionContextKey struct{}
+
+type untrackedRecoveryContextKey struct{}
+
+// WithResolution returns a copy of the given context carrying the given
+// resolution.
+func WithResolution(ctx context.Context, r Resolution) context.Context {
+	return context.WithValue(ctx, resolutionContextKey{}, r)
+}
+
+// ResolutionFromContext returns the resolution carried by the given context,
+// if any.
+func ResolutionFromContext(ctx context.Context) (Resolution, bool) {
+	if ctx == nil {
+		return Resolution{}, false
+	}
+	r, ok := ctx.Value(resolutionContextKey{}).(Resolution)
+	return r, ok
+}
+
+// WithUntrackedRecovery returns a copy of the given context marking the
+// event being posted as a success of an involved object whose previous state
+// is unknown, e.g. after a restart of the controller. Such an event may
+// still resolve a failure reported before.
+func WithUntrackedRecovery(ctx context.Context) context.Context {
+	return context.WithValue(ctx, untrackedRecoveryContextKey{}, true)
+}
+
+// IsUntrackedRecovery returns if the given context marks the event being
+// posted as a success of an involved object whose previous state is unknown.
+func IsUntrackedRecovery(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	untracked, _ := ctx.Value(untrackedRecoveryContextKey{}).(bool)
+	return untracked
+}
+
+// resolvedMessage returns the message of the given event, annotated with the
+// failure duration if the event resolves a previously reported failure.
+func resolvedMessage(ctx context.Context, event eventv1.Event) string {
+	r, ok := ResolutionFromContext(ctx)
+	if !ok {
+		return event.Message
+	}
+	return fmt.Sprintf("Resolved after %s: %s", event.Timestamp.Sub(r.FailingSince).Round(time.Second), event.Message)
+}
@@ -28,6 +28,8 @@ import (
 
 // Rocket holds the hook URL
 type Rocket struct {
+	egress
+
 	URL      string
 	ProxyURL string
 	Username string
@@ -58,6 +60,8 @@ func (s *Rocket) Post(ctx context.Context, event eventv1.Event) error {
 		return nil
 	}
 
+	event.Message = resolvedMessage(ctx, event)
+
 	payload := SlackPayload{
 		Channel:  s.Channel,
 		Username: s.Username,
@@ -83,7 +87,7 @@ func (s *Rocket) Post(ctx context.Context, event eventv1.Event) error {
 
 	payload.Attachments = []SlackAttachment{a}
 
-	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, payload)
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload)
 	if err != nil {
 		return fmt.Errorf("postMessage failed: %w", err)
 	}
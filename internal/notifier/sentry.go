@@ -18,10 +18,8 @@ package notifier
 
 import (
 	"context"
-	"crypto/tls"
 	"crypto/x509"
 	"fmt"
-	"net/http"
 
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 	"github.com/getsentry/sentry-go"
@@ -29,32 +27,25 @@ import (
 
 // Sentry holds the client instance
 type Sentry struct {
+	egress
+
 	Client *sentry.Client
 }
 
 // NewSentry creates a Sentry client from the provided Data Source Name (DSN)
 func NewSentry(certPool *x509.CertPool, dsn string, environment string) (*Sentry, error) {
-	tr := &http.Transport{}
-	if certPool != nil {
-		tr = &http.Transport{
-			TLSClientConfig: &tls.Config{
-				RootCAs: certPool,
-			},
-		}
-	}
+	s := &Sentry{}
 	client, err := sentry.NewClient(sentry.ClientOptions{
 		Dsn:              dsn,
 		Environment:      environment,
-		HTTPTransport:    tr,
+		HTTPTransport:    s.newHTTPTransport(certPool),
 		TracesSampleRate: 1,
 	})
 	if err != nil {
 		return nil, err
 	}
-
-	return &Sentry{
-		Client: client,
-	}, nil
+	s.Client = client
+	return s, nil
 }
 
 // Post event to Sentry
@@ -29,6 +29,8 @@ import (
 
 // Slack holds the hook URL
 type Slack struct {
+	egress
+
 	URL      string
 	ProxyURL string
 	Token    string
@@ -86,6 +88,8 @@ func (s *Slack) Post(ctx context.Context, event eventv1.Event) error {
 		return nil
 	}
 
+	event.Message = resolvedMessage(ctx, event)
+
 	payload := SlackPayload{
 		Username: s.Username,
 	}
@@ -118,7 +122,7 @@ func (s *Slack) Post(ctx context.Context, event eventv1.Event) error {
 
 	payload.Attachments = []SlackAttachment{a}
 
-	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, payload, func(request *retryablehttp.Request) {
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload, func(request *retryablehttp.Request) {
 		if s.Token != "" {
 			request.Header.Add("Authorization", "Bearer "+s.Token)
 		}
@@ -0,0 +1,351 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// smtpHTMLTemplate is the HTML body of the emails.
+var smtpHTMLTemplate = template.Must(template.New("smtp").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif;">
+<h3 style="color: {{ if eq .Severity "error" }}#d73a49{{ else }}#28a745{{ end }};">{{ .Title }}</h3>
+<p style="white-space: pre-wrap;">{{ .Message }}</p>
+{{- if .Metadata }}
+<table style="border-collapse: collapse;">
+{{- range .Metadata }}
+<tr><th style="text-align: left; padding: 4px 8px; border: 1px solid #ddd;">{{ .Key }}</th><td style="padding: 4px 8px; border: 1px solid #ddd;">{{ .Value }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`))
+
+type (
+	// SMTP holds an SMTP client and the email sender and recipients.
+	SMTP struct {
+		egress
+
+		from   string
+		to     []string
+		client interface {
+			send(ctx context.Context, from string, to []string, msg []byte) (err error)
+		}
+	}
+
+	smtpClient struct {
+		egress    *egress
+		host      string
+		port      string
+		implicit  bool
+		username  string
+		password  string
+		tlsConfig *tls.Config
+	}
+
+	// smtpMetadata is a row of the metadata table of the emails.
+	smtpMetadata struct {
+		Key   string
+		Value string
+	}
+)
+
+// NewSMTP creates an SMTP notifier sending emails to the given comma-separated
+// recipients, through the server at the given smtp:// or smtps:// URL. With
+// the smtp scheme, STARTTLS is used when supported by the server, and is
+// required when CA certificates are given. With the smtps scheme, implicit
+// TLS is used. The sender is read from the secret data, defaulting to the
+// username.
+func NewSMTP(address, recipients, username, password string,
+	certPool *x509.CertPool, secretData map[string][]byte) (*SMTP, error) {
+	if address == "" {
+		return nil, errors.New("SMTP server (address) cannot be empty")
+	}
+	server, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP server address: %w", err)
+	}
+
+	client := &smtpClient{
+		host:     server.Hostname(),
+		port:     server.Port(),
+		username: username,
+		password: password,
+	}
+	switch server.Scheme {
+	case "smtp":
+		if client.port == "" {
+			client.port = "587"
+		}
+	case "smtps":
+		client.implicit = true
+		if client.port == "" {
+			client.port = "465"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported SMTP server scheme '%s', must be smtp or smtps", server.Scheme)
+	}
+	if client.host == "" {
+		return nil, errors.New("SMTP server (address) must have a host")
+	}
+	if client.implicit || certPool != nil {
+		client.tlsConfig = &tls.Config{
+			RootCAs:    certPool,
+			ServerName: client.host,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	var to []string
+	for _, recipient := range strings.Split(recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient == "" {
+			continue
+		}
+		addr, err := mail.ParseAddress(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP recipient '%s': %w", recipient, err)
+		}
+		to = append(to, addr.Address)
+	}
+	if len(to) == 0 {
+		return nil, errors.New("SMTP recipients (channel) cannot be empty")
+	}
+
+	from := username
+	if v, ok := secretData["from"]; ok {
+		from = strings.TrimSpace(string(v))
+	}
+	if from == "" {
+		return nil, errors.New("SMTP sender cannot be empty")
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("invalid SMTP sender '%s': %w", from, err)
+	}
+
+	s := &SMTP{
+		from:   from,
+		to:     to,
+		client: client,
+	}
+	client.egress = &s.egress
+	return s, nil
+}
+
+// Post sends Flux events as emails.
+func (s *SMTP) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	msg, err := s.formatMessage(event)
+	if err != nil {
+		return fmt.Errorf("error formatting email: %w", err)
+	}
+
+	from, _ := mail.ParseAddress(s.from)
+	if err := s.client.send(ctx, from.Address, s.to, msg); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	// debug log
+	log.FromContext(ctx).V(1).Info("Event sent by email", "recipients", len(s.to))
+
+	return nil
+}
+
+// formatMessage returns the multipart email of the given event, with a
+// plain-text and an HTML body.
+func (s *SMTP) formatMessage(event eventv1.Event) ([]byte, error) {
+	obj := event.InvolvedObject
+	title := fmt.Sprintf("%s/%s.%s", strings.ToLower(obj.Kind), obj.Name, obj.Namespace)
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(event.Severity), title)
+	if event.Reason != "" {
+		subject = fmt.Sprintf("%s: %s", subject, strings.Join(splitCamelcase(event.Reason), " "))
+	}
+
+	var metadata []smtpMetadata
+	for _, key := range slices.Sorted(maps.Keys(event.Metadata)) {
+		metadata = append(metadata, smtpMetadata{Key: key, Value: event.Metadata[key]})
+	}
+
+	var text bytes.Buffer
+	fmt.Fprintf(&text, "%s\n\n%s\n", title, event.Message)
+	if len(metadata) > 0 {
+		text.WriteString("\n")
+		for _, m := range metadata {
+			fmt.Fprintf(&text, "%s: %s\n", m.Key, m.Value)
+		}
+	}
+
+	var html bytes.Buffer
+	err := smtpHTMLTemplate.Execute(&html, struct {
+		Title    string
+		Severity string
+		Message  string
+		Metadata []smtpMetadata
+	}{
+		Title:    title,
+		Severity: event.Severity,
+		Message:  event.Message,
+		Metadata: metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=UTF-8", text.Bytes()},
+		{"text/html; charset=UTF-8", html.Bytes()},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	messageID := make([]byte, 16)
+	if _, err := rand.Read(messageID); err != nil {
+		return nil, err
+	}
+	from, _ := mail.ParseAddress(s.from)
+	_, domain, _ := strings.Cut(from.Address, "@")
+
+	var msg bytes.Buffer
+	for _, header := range [][2]string{
+		{"From", from.String()},
+		{"To", strings.Join(s.to, ", ")},
+		{"Subject", mime.QEncoding.Encode("UTF-8", subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", fmt.Sprintf("<%s@%s>", hex.EncodeToString(messageID), domain)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%s", body.Boundary())},
+	} {
+		fmt.Fprintf(&msg, "%s: %s\r\n", header[0], header[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(buf.Bytes())
+	return msg.Bytes(), nil
+}
+
+func (s *smtpClient) send(ctx context.Context, from string, to []string, msg []byte) (err error) {
+	conn, err := s.egress.newEgressDialer(nil).DialContext(ctx, "tcp", net.JoinHostPort(s.host, s.port))
+	if err != nil {
+		return fmt.Errorf("error connecting to server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if s.implicit {
+		tlsConn := tls.Client(conn, s.tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fmt.Errorf("error connecting to server: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("error connecting to server: %w", err)
+	}
+	defer c.Close()
+
+	if !s.implicit {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			tlsConfig := s.tlsConfig
+			if tlsConfig == nil {
+				tlsConfig = &tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}
+			}
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return fmt.Errorf("error starting TLS: %w", err)
+			}
+		} else if s.tlsConfig != nil {
+			return errors.New("server does not support STARTTLS")
+		}
+	}
+
+	if s.username != "" && s.password != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("error authenticating: %w", err)
+		}
+	}
+
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := c.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,347 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// smtpMail is an email received by the fakeSMTPServer.
+type smtpMail struct {
+	tls  bool
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// fakeSMTPServer is an in-process SMTP server, supporting STARTTLS when a
+// TLS config is given, and recording the received emails.
+type fakeSMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+	mails     chan smtpMail
+}
+
+func newFakeSMTPServer(t *testing.T, implicitTLS bool, tlsConfig *tls.Config) *fakeSMTPServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if implicitTLS {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	s := &fakeSMTPServer{
+		listener:  listener,
+		tlsConfig: tlsConfig,
+		mails:     make(chan smtpMail, 1),
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn, implicitTLS)
+		}
+	}()
+	return s
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn, isTLS bool) {
+	defer func() { conn.Close() }()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		io.WriteString(conn, line+"\r\n")
+	}
+
+	m := smtpMail{tls: isTLS}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+		switch strings.ToUpper(cmd) {
+		case "EHLO":
+			reply("250-localhost")
+			if s.tlsConfig != nil && !m.tls {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 Ready to start TLS")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			r = bufio.NewReader(conn)
+			m.tls = true
+		case "AUTH":
+			_, resp, _ := strings.Cut(arg, " ")
+			auth, _ := base64.StdEncoding.DecodeString(resp)
+			m.auth = string(auth)
+			reply("235 Authentication successful")
+		case "MAIL":
+			m.from = strings.Trim(strings.TrimPrefix(arg, "FROM:"), "<>")
+			reply("250 OK")
+		case "RCPT":
+			m.to = append(m.to, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			m.data = data.String()
+			s.mails <- m
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// newSMTPTestTLS returns the server TLS config and CA pool of a test
+// certificate valid for 127.0.0.1.
+func newSMTPTestTLS(t *testing.T) (*tls.Config, *x509.CertPool) {
+	srv := httptest.NewUnstartedServer(nil)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	certPool := x509.NewCertPool()
+	certPool.AddCert(srv.Certificate())
+	return &tls.Config{Certificates: srv.TLS.Certificates}, certPool
+}
+
+func TestNewSMTP(t *testing.T) {
+	tests := []struct {
+		name        string
+		address     string
+		recipients  string
+		username    string
+		secretData  map[string][]byte
+		expectedErr string
+		expectedTo  []string
+		implicit    bool
+		port        string
+	}{
+		{
+			name:        "empty address",
+			recipients:  "ops@example.com",
+			expectedErr: "SMTP server (address) cannot be empty",
+		},
+		{
+			name:        "unsupported scheme",
+			address:     "smtp.example.com:587",
+			recipients:  "ops@example.com",
+			expectedErr: "unsupported SMTP server scheme",
+		},
+		{
+			name:        "empty recipients",
+			address:     "smtp://smtp.example.com",
+			recipients:  " , ",
+			expectedErr: "SMTP recipients (channel) cannot be empty",
+		},
+		{
+			name:        "invalid recipient",
+			address:     "smtp://smtp.example.com",
+			recipients:  "ops",
+			expectedErr: "invalid SMTP recipient 'ops'",
+		},
+		{
+			name:        "empty sender",
+			address:     "smtp://smtp.example.com",
+			recipients:  "ops@example.com",
+			expectedErr: "SMTP sender cannot be empty",
+		},
+		{
+			name:       "STARTTLS with sender from username",
+			address:    "smtp://smtp.example.com",
+			recipients: "ops@example.com, Dev Team <dev@example.com>",
+			username:   "flux@example.com",
+			expectedTo: []string{"ops@example.com", "dev@example.com"},
+			port:       "587",
+		},
+		{
+			name:       "implicit TLS with sender from secret",
+			address:    "smtps://smtp.example.com",
+			recipients: "ops@example.com",
+			username:   "apikey",
+			secretData: map[string][]byte{"from": []byte("Flux <flux@example.com>")},
+			expectedTo: []string{"ops@example.com"},
+			implicit:   true,
+			port:       "465",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewSMTP(tt.address, tt.recipients, tt.username, "pass", nil, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(provider.to).To(Equal(tt.expectedTo))
+
+			client := provider.client.(*smtpClient)
+			g.Expect(client.implicit).To(Equal(tt.implicit))
+			g.Expect(client.port).To(Equal(tt.port))
+		})
+	}
+}
+
+func TestSMTPPost(t *testing.T) {
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Kustomization",
+			Namespace: "flux-system",
+			Name:      "apps",
+		},
+		Severity: eventv1.EventSeverityError,
+		Reason:   "HealthCheckFailed",
+		Message:  "Deployment/apps/podinfo <not ready>",
+		Metadata: map[string]string{
+			"revision": "main@sha1:abc",
+			"cluster":  "prod",
+		},
+	}
+
+	serverTLS, certPool := newSMTPTestTLS(t)
+	tests := []struct {
+		name        string
+		scheme      string
+		implicitTLS bool
+		serverTLS   *tls.Config
+		certPool    *x509.CertPool
+		expectedTLS bool
+	}{
+		{
+			name:   "plain",
+			scheme: "smtp",
+		},
+		{
+			name:        "STARTTLS",
+			scheme:      "smtp",
+			serverTLS:   serverTLS,
+			certPool:    certPool,
+			expectedTLS: true,
+		},
+		{
+			name:        "implicit TLS",
+			scheme:      "smtps",
+			implicitTLS: true,
+			serverTLS:   serverTLS,
+			certPool:    certPool,
+			expectedTLS: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server := newFakeSMTPServer(t, tt.implicitTLS, tt.serverTLS)
+			provider, err := NewSMTP(tt.scheme+"://"+server.listener.Addr().String(),
+				"ops@example.com,dev@example.com", "flux", "s3cr3t", tt.certPool,
+				map[string][]byte{"from": []byte("Flux <flux@example.com>")})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			g.Expect(provider.Post(ctx, event)).To(Succeed())
+
+			m := <-server.mails
+			g.Expect(m.tls).To(Equal(tt.expectedTLS))
+			g.Expect(m.auth).To(Equal("\x00flux\x00s3cr3t"))
+			g.Expect(m.from).To(Equal("flux@example.com"))
+			g.Expect(m.to).To(Equal([]string{"ops@example.com", "dev@example.com"}))
+
+			msg, err := mail.ReadMessage(strings.NewReader(m.data))
+			g.Expect(err).ToNot(HaveOccurred())
+			subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(subject).To(Equal("[ERROR] kustomization/apps.flux-system: Health Check Failed"))
+			g.Expect(msg.Header.Get("From")).To(Equal(`"Flux" <flux@example.com>`))
+
+			mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(mediaType).To(Equal("multipart/alternative"))
+			parts := multipart.NewReader(msg.Body, params["boundary"])
+
+			text, err := parts.NextPart()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(text.Header.Get("Content-Type")).To(HavePrefix("text/plain"))
+			textBody, _ := io.ReadAll(text)
+			g.Expect(string(textBody)).To(ContainSubstring("Deployment/apps/podinfo <not ready>"))
+			g.Expect(string(textBody)).To(ContainSubstring("cluster: prod\r\nrevision: main@sha1:abc"))
+
+			html, err := parts.NextPart()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(html.Header.Get("Content-Type")).To(HavePrefix("text/html"))
+			htmlBody, _ := io.ReadAll(html)
+			g.Expect(string(htmlBody)).To(ContainSubstring("Deployment/apps/podinfo &lt;not ready&gt;"))
+			g.Expect(string(htmlBody)).To(ContainSubstring("<th style=\"text-align: left; padding: 4px 8px; border: 1px solid #ddd;\">revision</th>"))
+		})
+	}
+}
+
+func TestSMTPPost_commitStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	provider, err := NewSMTP("smtp://127.0.0.1:1", "ops@example.com", "flux@example.com", "", nil, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	event := eventv1.Event{Metadata: map[string]string{
+		eventv1.MetaCommitStatusKey: eventv1.MetaCommitStatusUpdateValue,
+	}}
+	g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+}
@@ -0,0 +1,307 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/hashicorp/go-retryablehttp"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// splunkHECEventPath is the HEC endpoint receiving JSON events, used when
+	// the address has no path.
+	splunkHECEventPath = "/services/collector/event"
+	// splunkHECDefaultSourceType is the sourcetype of the events, when not
+	// set in the Secret.
+	splunkHECDefaultSourceType = "_json"
+	// splunkHECMaxBatchSize is the maximum number of events per request.
+	splunkHECMaxBatchSize = 100
+	// splunkHECDefaultBatchInterval is the maximum duration the events wait
+	// for their batch to be sent, when not set in the Secret.
+	splunkHECDefaultBatchInterval = time.Second
+)
+
+// SplunkHEC holds the Splunk HTTP Event Collector URL, token and event fields
+type SplunkHEC struct {
+	egress
+
+	URL           string
+	ProxyURL      string
+	Token         string
+	CertPool      *x509.CertPool
+	Index         string
+	SourceType    string
+	Source        string
+	Host          string
+	BatchSize     int
+	BatchInterval time.Duration
+
+	// batcher holds the pending batches of the events when batching is
+	// enabled, and provider identifies the Provider of the events in the
+	// reported batch errors.
+	batcher  *SplunkHECBatcher
+	provider splunkHECProvider
+}
+
+// SplunkHECEvent holds an event sent to the HTTP Event Collector
+type SplunkHECEvent struct {
+	Time       float64       `json:"time,omitempty"`
+	Host       string        `json:"host,omitempty"`
+	Source     string        `json:"source,omitempty"`
+	SourceType string        `json:"sourcetype,omitempty"`
+	Index      string        `json:"index,omitempty"`
+	Event      eventv1.Event `json:"event"`
+}
+
+// splunkHECBatchKey identifies the batches of events sent together, as they
+// are sent to the same collector with the same token.
+type splunkHECBatchKey struct {
+	url      string
+	token    string
+	proxyURL string
+	certPool *x509.CertPool
+}
+
+// splunkHECProvider identifies the Provider of the events of a batch.
+type splunkHECProvider struct {
+	namespace string
+	name      string
+}
+
+// splunkHECBatch holds the events waiting to be sent in the same request.
+type splunkHECBatch struct {
+	// ctx holds the values of the context of the first event, without its
+	// cancellation, and timeout is the duration left before its deadline.
+	ctx       context.Context
+	timeout   time.Duration
+	events    [][]byte
+	providers map[splunkHECProvider]int
+}
+
+// SplunkHECBatchErrorFunc is called with the number of events of a Provider
+// in a batch which failed to be sent to the HTTP Event Collector.
+type SplunkHECBatchErrorFunc func(providerNamespace, providerName string, events int, err error)
+
+// SplunkHECBatcher holds the pending batches of the events sent to the Splunk
+// HTTP Event Collectors, so that the events of the notifiers sending to the
+// same collector with the same token are sent together. The events are
+// acknowledged once queued, and their batches are sent in the background.
+type SplunkHECBatcher struct {
+	mu      sync.Mutex
+	batches map[splunkHECBatchKey]*splunkHECBatch
+	onError SplunkHECBatchErrorFunc
+}
+
+// NewSplunkHECBatcher returns a SplunkHECBatcher reporting the batches which
+// failed to be sent to the given function, or in the logs of the context of
+// their first event if nil.
+func NewSplunkHECBatcher(onError SplunkHECBatchErrorFunc) *SplunkHECBatcher {
+	return &SplunkHECBatcher{
+		batches: make(map[splunkHECBatchKey]*splunkHECBatch),
+		onError: onError,
+	}
+}
+
+// NewSplunkHEC validates the HEC URL and token and returns a SplunkHEC object.
+// The index, sourcetype, source and host fields of the events, and the size
+// and interval of their batches, are read from the secret data.
+func NewSplunkHEC(address, proxyURL string, certPool *x509.CertPool, token string, secretData map[string][]byte) (*SplunkHEC, error) {
+	u, err := url.ParseRequestURI(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Splunk HEC URL %s: '%w'", address, err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = splunkHECEventPath
+	}
+
+	if token == "" {
+		return nil, errors.New("empty Splunk HEC token")
+	}
+
+	s := &SplunkHEC{
+		URL:           u.String(),
+		ProxyURL:      proxyURL,
+		Token:         token,
+		CertPool:      certPool,
+		Index:         strings.TrimSpace(string(secretData["index"])),
+		SourceType:    strings.TrimSpace(string(secretData["sourcetype"])),
+		Source:        strings.TrimSpace(string(secretData["source"])),
+		Host:          strings.TrimSpace(string(secretData["host"])),
+		BatchSize:     1,
+		BatchInterval: splunkHECDefaultBatchInterval,
+	}
+	if s.SourceType == "" {
+		s.SourceType = splunkHECDefaultSourceType
+	}
+	if v, ok := secretData["batchSize"]; ok {
+		size, err := strconv.Atoi(strings.TrimSpace(string(v)))
+		if err != nil || size < 1 || size > splunkHECMaxBatchSize {
+			return nil, fmt.Errorf("invalid Splunk HEC batch size '%s', must be between 1 and %d",
+				string(v), splunkHECMaxBatchSize)
+		}
+		s.BatchSize = size
+	}
+	if v, ok := secretData["batchInterval"]; ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(string(v)))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid Splunk HEC batch interval '%s', must be a positive duration", string(v))
+		}
+		s.BatchInterval = interval
+	}
+	if s.BatchSize > 1 {
+		s.batcher = NewSplunkHECBatcher(nil)
+	}
+
+	return s, nil
+}
+
+// Post sends the event to the HTTP Event Collector. When batching is enabled,
+// the event is queued to be sent with the other events posted to the same
+// collector within the batch interval, up to the batch size, and Post returns
+// once the event is queued. The errors of the batches are reported by the
+// SplunkHECBatcher.
+func (s *SplunkHEC) Post(ctx context.Context, event eventv1.Event) error {
+	// Skip Git commit status update event.
+	if event.HasMetadata(eventv1.MetaCommitStatusKey, eventv1.MetaCommitStatusUpdateValue) {
+		return nil
+	}
+
+	event.Message = resolvedMessage(ctx, event)
+	source := s.Source
+	if source == "" {
+		source = event.ReportingController
+	}
+	host := s.Host
+	if host == "" {
+		host = event.ReportingInstance
+	}
+	hecEvent := SplunkHECEvent{
+		Host:       host,
+		Source:     source,
+		SourceType: s.SourceType,
+		Index:      s.Index,
+		Event:      event,
+	}
+	if !event.Timestamp.IsZero() {
+		hecEvent.Time = float64(event.Timestamp.UnixMilli()) / 1000
+	}
+	data, err := json.Marshal(hecEvent)
+	if err != nil {
+		return fmt.Errorf("marshalling Splunk HEC event failed: %w", err)
+	}
+
+	if s.BatchSize == 1 {
+		return s.send(ctx, data)
+	}
+
+	s.batcher.enqueue(ctx, s, data)
+	return nil
+}
+
+// enqueue adds the given event of the given notifier to the pending batch of
+// its collector, creating it if needed, and sends the batch once full.
+func (b *SplunkHECBatcher) enqueue(ctx context.Context, s *SplunkHEC, data []byte) {
+	key := splunkHECBatchKey{url: s.URL, token: s.Token, proxyURL: s.ProxyURL, certPool: s.CertPool}
+
+	b.mu.Lock()
+	batch, ok := b.batches[key]
+	if !ok {
+		batch = &splunkHECBatch{
+			ctx:       context.WithoutCancel(ctx),
+			providers: make(map[splunkHECProvider]int),
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			batch.timeout = time.Until(deadline)
+		}
+		b.batches[key] = batch
+		time.AfterFunc(s.BatchInterval, func() {
+			if b.detach(key, batch) {
+				b.flush(s, batch)
+			}
+		})
+	}
+	batch.events = append(batch.events, data)
+	batch.providers[s.provider]++
+	full := len(batch.events) >= s.BatchSize
+	if full {
+		delete(b.batches, key)
+	}
+	b.mu.Unlock()
+
+	if full {
+		go b.flush(s, batch)
+	}
+}
+
+// detach removes the given batch from the pending batches, returning false
+// when it was already sent.
+func (b *SplunkHECBatcher) detach(key splunkHECBatchKey, batch *splunkHECBatch) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.batches[key] != batch {
+		return false
+	}
+	delete(b.batches, key)
+	return true
+}
+
+// flush sends the events of the given batch in a single request with the
+// given notifier, within the timeout of the first event of the batch, and
+// reports the error of the request if any.
+func (b *SplunkHECBatcher) flush(s *SplunkHEC, batch *splunkHECBatch) {
+	ctx := batch.ctx
+	if batch.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, batch.timeout)
+		defer cancel()
+	}
+	err := s.send(ctx, bytes.Join(batch.events, []byte("\n")))
+	if err == nil {
+		return
+	}
+	if b.onError == nil {
+		log.FromContext(ctx).Error(err, "failed to send Splunk HEC batch", "events", len(batch.events))
+		return
+	}
+	for provider, events := range batch.providers {
+		b.onError(provider.namespace, provider.name, events, err)
+	}
+}
+
+// send posts the given events to the collector.
+func (s *SplunkHEC) send(ctx context.Context, data []byte) error {
+	err := postData(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), data, func(request *retryablehttp.Request) {
+		request.Header.Set("Authorization", "Splunk "+s.Token)
+	})
+	if err != nil {
+		return fmt.Errorf("postMessage failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestNewSplunkHEC(t *testing.T) {
+	tests := []struct {
+		name          string
+		address       string
+		token         string
+		secretData    map[string][]byte
+		expectedErr   string
+		expectedURL   string
+		batchSize     int
+		batchInterval time.Duration
+	}{
+		{
+			name:        "invalid URL",
+			address:     "splunk.example.com",
+			token:       "token",
+			expectedErr: "invalid Splunk HEC URL",
+		},
+		{
+			name:        "empty token",
+			address:     "https://splunk.example.com:8088",
+			expectedErr: "empty Splunk HEC token",
+		},
+		{
+			name:        "invalid batch size",
+			address:     "https://splunk.example.com:8088",
+			token:       "token",
+			secretData:  map[string][]byte{"batchSize": []byte("0")},
+			expectedErr: "invalid Splunk HEC batch size '0'",
+		},
+		{
+			name:        "invalid batch interval",
+			address:     "https://splunk.example.com:8088",
+			token:       "token",
+			secretData:  map[string][]byte{"batchInterval": []byte("soon")},
+			expectedErr: "invalid Splunk HEC batch interval 'soon'",
+		},
+		{
+			name:          "default event path",
+			address:       "https://splunk.example.com:8088",
+			token:         "token",
+			expectedURL:   "https://splunk.example.com:8088/services/collector/event",
+			batchSize:     1,
+			batchInterval: splunkHECDefaultBatchInterval,
+		},
+		{
+			name:          "custom path with batching",
+			address:       "https://http-inputs-acme.splunkcloud.com/services/collector",
+			token:         "token",
+			secretData:    map[string][]byte{"batchSize": []byte("10"), "batchInterval": []byte("200ms")},
+			expectedURL:   "https://http-inputs-acme.splunkcloud.com/services/collector",
+			batchSize:     10,
+			batchInterval: 200 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider, err := NewSplunkHEC(tt.address, "", nil, tt.token, tt.secretData)
+			if tt.expectedErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectedErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(provider.URL).To(Equal(tt.expectedURL))
+			g.Expect(provider.BatchSize).To(Equal(tt.batchSize))
+			g.Expect(provider.BatchInterval).To(Equal(tt.batchInterval))
+		})
+	}
+}
+
+// splunkHECServer is an HTTP Event Collector stand-in, recording the events
+// of each request.
+type splunkHECServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests [][]SplunkHECEvent
+}
+
+func newSplunkHECServer(t *testing.T) *splunkHECServer {
+	s := &splunkHECServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != splunkHECEventPath || r.Header.Get("Authorization") != "Splunk token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"text":"Invalid token","code":4}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var events []SplunkHECEvent
+		dec := json.NewDecoder(bytes.NewReader(body))
+		for dec.More() {
+			var e SplunkHECEvent
+			if err := dec.Decode(&e); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			events = append(events, e)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, events)
+		s.mu.Unlock()
+		w.Write([]byte(`{"text":"Success","code":0}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *splunkHECServer) received() [][]SplunkHECEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func TestSplunkHECPost(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := newSplunkHECServer(t)
+	provider, err := NewSplunkHEC(srv.URL, "", nil, "token", map[string][]byte{
+		"index":      []byte("flux"),
+		"sourcetype": []byte("flux:event"),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Kustomization",
+			Namespace: "flux-system",
+			Name:      "apps",
+		},
+		Severity:            eventv1.EventSeverityInfo,
+		Timestamp:           metav1.NewTime(time.UnixMilli(1700000000123)),
+		Message:             "applied",
+		Reason:              "ReconciliationSucceeded",
+		ReportingController: "kustomize-controller",
+		ReportingInstance:   "kustomize-controller-7f9c",
+	}
+	g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+
+	requests := srv.received()
+	g.Expect(requests).To(HaveLen(1))
+	g.Expect(requests[0]).To(HaveLen(1))
+	e := requests[0][0]
+	g.Expect(e.Time).To(Equal(1700000000.123))
+	g.Expect(e.Index).To(Equal("flux"))
+	g.Expect(e.SourceType).To(Equal("flux:event"))
+	g.Expect(e.Source).To(Equal("kustomize-controller"))
+	g.Expect(e.Host).To(Equal("kustomize-controller-7f9c"))
+	g.Expect(e.Event.Message).To(Equal("applied"))
+	g.Expect(e.Event.InvolvedObject.Name).To(Equal("apps"))
+}
+
+func TestSplunkHECPost_batch(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := newSplunkHECServer(t)
+	provider, err := NewSplunkHEC(srv.URL, "", nil, "token", map[string][]byte{
+		"batchSize":     []byte("3"),
+		"batchInterval": []byte("100ms"),
+		"source":        []byte("flux"),
+		"host":          []byte("prod"),
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// A full batch is sent at once, the events being acknowledged once queued.
+	for range 3 {
+		g.Expect(provider.Post(ctx, eventv1.Event{Message: "full"})).To(Succeed())
+	}
+	g.Eventually(srv.received, time.Second, 10*time.Millisecond).Should(HaveLen(1))
+	g.Expect(srv.received()[0]).To(HaveLen(3))
+
+	// A partial batch is sent after the batch interval.
+	start := time.Now()
+	g.Expect(provider.Post(ctx, eventv1.Event{Message: "partial"})).To(Succeed())
+	g.Eventually(srv.received, time.Second, 10*time.Millisecond).Should(HaveLen(2))
+	g.Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+	requests := srv.received()
+	g.Expect(requests[1]).To(HaveLen(1))
+	g.Expect(requests[1][0].Source).To(Equal("flux"))
+	g.Expect(requests[1][0].Host).To(Equal("prod"))
+	g.Expect(requests[1][0].Event.Message).To(Equal("partial"))
+
+	// The batch is sent after the context of its first event is canceled.
+	cancelCtx, cancelPost := context.WithTimeout(context.Background(), 10*time.Second)
+	g.Expect(provider.Post(cancelCtx, eventv1.Event{Message: "canceled"})).To(Succeed())
+	cancelPost()
+	g.Eventually(srv.received, time.Second, 10*time.Millisecond).Should(HaveLen(3))
+}
+
+func TestSplunkHECPost_sharedBatcher(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := newSplunkHECServer(t)
+	batcher := NewSplunkHECBatcher(nil)
+	secretData := map[string][]byte{
+		"batchSize":     []byte("2"),
+		"batchInterval": []byte("10s"),
+	}
+	for _, name := range []string{"splunk-a", "splunk-b"} {
+		n, err := NewFactory(srv.URL, WithToken("token"), WithSecretData(secretData),
+			WithProviderName(name), WithSplunkHECBatcher(batcher)).Notifier(apiv1.SplunkHECProvider)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(n.Post(context.Background(), eventv1.Event{Message: name})).To(Succeed())
+	}
+
+	// The events of the notifiers sending to the same collector are batched
+	// together.
+	g.Eventually(srv.received, time.Second, 10*time.Millisecond).Should(HaveLen(1))
+	g.Expect(srv.received()[0]).To(HaveLen(2))
+}
+
+func TestSplunkHECPost_error(t *testing.T) {
+	g := NewWithT(t)
+
+	type batchError struct {
+		namespace string
+		name      string
+		events    int
+		err       error
+	}
+	errs := make(chan batchError, 2)
+	batcher := NewSplunkHECBatcher(func(namespace, name string, events int, err error) {
+		errs <- batchError{namespace: namespace, name: name, events: events, err: err}
+	})
+
+	srv := newSplunkHECServer(t)
+	n, err := NewFactory(srv.URL, WithToken("invalid"), WithSecretData(map[string][]byte{
+		"batchSize":     []byte("2"),
+		"batchInterval": []byte("10ms"),
+	}), WithProviderNamespace("apps"), WithProviderName("splunk"), WithSplunkHECBatcher(batcher)).
+		Notifier(apiv1.SplunkHECProvider)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The error of a batch is reported by the batcher once the event is
+	// acknowledged.
+	g.Expect(n.Post(context.Background(), eventv1.Event{Message: "test"})).To(Succeed())
+	var batchErr batchError
+	g.Eventually(errs, time.Second).Should(Receive(&batchErr))
+	g.Expect(batchErr.namespace).To(Equal("apps"))
+	g.Expect(batchErr.name).To(Equal("splunk"))
+	g.Expect(batchErr.events).To(Equal(1))
+	g.Expect(batchErr.err).To(MatchError(ContainSubstring("Invalid token")))
+
+	// The events are sent one per request without batching, and their
+	// errors returned.
+	provider, err := NewSplunkHEC(srv.URL, "", nil, "invalid", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = provider.Post(context.Background(), eventv1.Event{Message: "test"})
+	g.Expect(err).To(MatchError(ContainSubstring("Invalid token")))
+}
+
+func TestSplunkHECPost_commitStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	provider, err := NewSplunkHEC("http://127.0.0.1:1", "", nil, "token", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	event := eventv1.Event{Metadata: map[string]string{
+		eventv1.MetaCommitStatusKey: eventv1.MetaCommitStatusUpdateValue,
+	}}
+	g.Expect(provider.Post(context.Background(), event)).To(Succeed())
+}
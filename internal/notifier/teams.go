@@ -39,6 +39,8 @@ const (
 
 // MS Teams holds the incoming webhook URL
 type MSTeams struct {
+	egress
+
 	URL      string
 	ProxyURL string
 	CertPool *x509.CertPool
@@ -149,6 +151,8 @@ func (s *MSTeams) Post(ctx context.Context, event eventv1.Event) error {
 		return nil
 	}
 
+	event.Message = resolvedMessage(ctx, event)
+
 	objName := fmt.Sprintf("%s/%s.%s", strings.ToLower(event.InvolvedObject.Kind), event.InvolvedObject.Name, event.InvolvedObject.Namespace)
 
 	var payload any
@@ -161,7 +165,7 @@ func (s *MSTeams) Post(ctx context.Context, event eventv1.Event) error {
 		payload = buildMSTeamsAdaptiveCardPayload(&event, objName)
 	}
 
-	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, payload)
+	err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload)
 	if err != nil {
 		return fmt.Errorf("postMessage failed: %w", err)
 	}
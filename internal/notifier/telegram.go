@@ -34,6 +34,8 @@ func (t *Telegram) Post(ctx context.Context, event eventv1.Event) error {
 		return nil
 	}
 
+	event.Message = resolvedMessage(ctx, event)
+
 	emoji := "💫"
 	if event.Severity == eventv1.EventSeverityError {
 		emoji = "🚨"
@@ -24,12 +24,53 @@ import (
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/fluxcd/pkg/apis/meta"
 	"github.com/fluxcd/pkg/git"
 
 	giturls "github.com/chainguard-dev/git-urls"
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 )
 
+// reasonEmoji holds the emoji of an event reason, as an ntfy tag shortcode
+// and as a Unicode character.
+type reasonEmoji struct {
+	tag  string
+	char string
+}
+
+var (
+	reasonEmojis = map[string]reasonEmoji{
+		meta.ProgressingReason:          {tag: "hourglass_flowing_sand", char: "\u23f3"},
+		meta.ProgressingWithRetryReason: {tag: "hourglass_flowing_sand", char: "\u23f3"},
+		meta.DependencyNotReadyReason:   {tag: "link", char: "\U0001f517"},
+		meta.HealthCheckFailedReason:    {tag: "broken_heart", char: "\U0001f494"},
+		meta.SuspendedReason:            {tag: "zzz", char: "\U0001f4a4"},
+		"NewArtifact":                   {tag: "package", char: "\U0001f4e6"},
+	}
+	succeededEmoji = reasonEmoji{tag: "white_check_mark", char: "\u2705"}
+	failedEmoji    = reasonEmoji{tag: "x", char: "\u274c"}
+	errorEmoji     = reasonEmoji{tag: "rotating_light", char: "\U0001f6a8"}
+	infoEmoji      = reasonEmoji{tag: "information_source", char: "\u2139\ufe0f"}
+)
+
+// eventEmoji returns the emoji of the reason of the given event, falling back
+// to the emoji of its severity.
+func eventEmoji(event eventv1.Event) reasonEmoji {
+	if e, ok := reasonEmojis[event.Reason]; ok {
+		return e
+	}
+	switch {
+	case strings.HasSuffix(event.Reason, meta.SucceededReason):
+		return succeededEmoji
+	case strings.HasSuffix(event.Reason, meta.FailedReason):
+		return failedEmoji
+	case event.Severity == eventv1.EventSeverityError:
+		return errorEmoji
+	default:
+		return infoEmoji
+	}
+}
+
 func parseGitAddress(s string) (string, string, error) {
 	u, err := giturls.Parse(s)
 	if err != nil {
@@ -150,3 +150,25 @@ func TestUtil_BasicAuth(t *testing.T) {
 	s := basicAuth(username, password)
 	require.Equal(t, "dXNlcjpwYXNzd29yZA==", s)
 }
+
+func TestUtil_EventEmoji(t *testing.T) {
+	tests := []struct {
+		reason   string
+		severity string
+		expect   string
+	}{
+		{reason: "HealthCheckFailed", severity: eventv1.EventSeverityError, expect: "broken_heart"},
+		{reason: "Progressing", severity: eventv1.EventSeverityInfo, expect: "hourglass_flowing_sand"},
+		{reason: "UpgradeSucceeded", severity: eventv1.EventSeverityInfo, expect: "white_check_mark"},
+		{reason: "InstallFailed", severity: eventv1.EventSeverityError, expect: "x"},
+		{reason: "GitOperationFailure", severity: eventv1.EventSeverityError, expect: "rotating_light"},
+		{reason: "ChartPullSucceededWithWarnings", severity: eventv1.EventSeverityInfo, expect: "information_source"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			e := eventEmoji(eventv1.Event{Reason: tt.reason, Severity: tt.severity})
+			require.Equal(t, tt.expect, e.tag)
+			require.NotEmpty(t, e.char)
+		})
+	}
+}
@@ -43,6 +43,8 @@ import (
 
 // Webex holds the hook URL
 type Webex struct {
+	egress
+
 	// mandatory: this should be set to the universal webex API server https://webexapis.com/v1/messages
 	URL string
 	// mandatory: webex room ID, specifies on which webex space notifications must be sent
@@ -108,7 +110,7 @@ func (s *Webex) Post(ctx context.Context, event eventv1.Event) error {
 		Markdown: s.CreateMarkdown(&event),
 	}
 
-	if err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, payload, func(request *retryablehttp.Request) {
+	if err := postMessage(ctx, s.URL, s.ProxyURL, s.CertPool, s.egressPolicy(), payload, func(request *retryablehttp.Request) {
 		request.Header.Add("Authorization", "Bearer "+s.Token)
 	}); err != nil {
 		return fmt.Errorf("postMessage failed: %w", err)
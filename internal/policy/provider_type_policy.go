@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy holds the policies of the controller restricting the
+// notification resources, enforced both by the reconcilers and by the event
+// server.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+// ErrProviderTypeNotAllowed is returned when the type of a Provider is not
+// allowed in its namespace by the ProviderTypePolicy.
+var ErrProviderTypeNotAllowed = errors.New("provider type not allowed")
+
+// ProviderTypePolicy restricts the Provider types that can be used in the
+// namespaces. The first rule matching the namespace of a Provider applies,
+// the namespaces matched by no rule are not restricted. The ClusterProviders
+// are not restricted, as they can only be created by the cluster admins.
+type ProviderTypePolicy struct {
+	// Rules are the rules of the policy, in order.
+	Rules []ProviderTypeRule `json:"rules"`
+
+	selectors []labels.Selector
+}
+
+// ProviderTypeRule restricts the Provider types in the namespaces it
+// matches, either by name or by labels.
+type ProviderTypeRule struct {
+	// Namespaces are the names of the namespaces matched by the rule.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector matches the namespaces by their labels.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// AllowedTypes are the Provider types allowed in the matched namespaces.
+	AllowedTypes []string `json:"allowedTypes"`
+}
+
+// LoadProviderTypePolicy reads the ProviderTypePolicy from the given YAML
+// file.
+func LoadProviderTypePolicy(path string) (*ProviderTypePolicy, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider type policy: %w", err)
+	}
+	var policy ProviderTypePolicy
+	if err := yaml.UnmarshalStrict(b, &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse provider type policy: %w", err)
+	}
+	if err := policy.compile(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (p *ProviderTypePolicy) compile() error {
+	p.selectors = make([]labels.Selector, len(p.Rules))
+	for i, rule := range p.Rules {
+		if len(rule.Namespaces) == 0 && rule.NamespaceSelector == nil {
+			return fmt.Errorf("invalid provider type policy rule %d: namespaces or namespaceSelector must be set", i)
+		}
+		if rule.NamespaceSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(rule.NamespaceSelector)
+		if err != nil {
+			return fmt.Errorf("invalid provider type policy rule %d: %w", i, err)
+		}
+		p.selectors[i] = selector
+	}
+	return nil
+}
+
+// Validate returns an error wrapping ErrProviderTypeNotAllowed if the type of
+// the given Provider is not allowed in its namespace. The policy allows all
+// the Providers if nil.
+func (p *ProviderTypePolicy) Validate(ctx context.Context, reader client.Reader, provider *apiv1beta3.Provider) error {
+	if p == nil || len(p.Rules) == 0 || provider.Namespace == "" || provider.Kind == apiv1beta3.ClusterProviderKind {
+		return nil
+	}
+
+	var nsLabels labels.Set
+	for i, rule := range p.Rules {
+		matches := slices.Contains(rule.Namespaces, provider.Namespace)
+		if !matches && p.selectors[i] != nil {
+			if nsLabels == nil {
+				var ns corev1.Namespace
+				if err := reader.Get(ctx, types.NamespacedName{Name: provider.Namespace}, &ns); err != nil {
+					return fmt.Errorf("failed to read namespace '%s': %w", provider.Namespace, err)
+				}
+				nsLabels = labels.Set(ns.GetLabels())
+			}
+			matches = p.selectors[i].Matches(nsLabels)
+		}
+		if !matches {
+			continue
+		}
+
+		if slices.Contains(rule.AllowedTypes, provider.Spec.Type) {
+			return nil
+		}
+		return fmt.Errorf("%w: type '%s' is not allowed in namespace '%s', allowed types: %v",
+			ErrProviderTypeNotAllowed, provider.Spec.Type, provider.Namespace, rule.AllowedTypes)
+	}
+	return nil
+}
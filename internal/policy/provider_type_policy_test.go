@@ -0,0 +1,132 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+const testProviderTypePolicy = `
+rules:
+- namespaces: [flux-system]
+  allowedTypes: [generic, githubdispatch, slack]
+- namespaceSelector:
+    matchLabels:
+      tenant: "true"
+  allowedTypes: [slack, msteams]
+`
+
+func loadTestProviderTypePolicy(t *testing.T, policy string) (*ProviderTypePolicy, error) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(policy), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadProviderTypePolicy(path)
+}
+
+func TestProviderTypePolicy_Validate(t *testing.T) {
+	policy, err := loadTestProviderTypePolicy(t, testProviderTypePolicy)
+	NewWithT(t).Expect(err).ToNot(HaveOccurred())
+
+	tenant := &corev1.Namespace{}
+	tenant.Name = "team-a"
+	tenant.Labels = map[string]string{"tenant": "true"}
+	other := &corev1.Namespace{}
+	other.Name = "other"
+	kubeClient := fakeclient.NewClientBuilder().WithObjects(tenant, other).Build()
+
+	tests := []struct {
+		name         string
+		kind         string
+		namespace    string
+		providerType string
+		wantErr      bool
+	}{
+		{
+			name:         "allowed by namespace name",
+			namespace:    "flux-system",
+			providerType: apiv1beta3.GitHubDispatchProvider,
+		},
+		{
+			name:         "allowed by namespace labels",
+			namespace:    "team-a",
+			providerType: apiv1beta3.SlackProvider,
+		},
+		{
+			name:         "not allowed by namespace labels",
+			namespace:    "team-a",
+			providerType: apiv1beta3.GenericProvider,
+			wantErr:      true,
+		},
+		{
+			name:         "namespace not restricted",
+			namespace:    "other",
+			providerType: apiv1beta3.GenericProvider,
+		},
+		{
+			name:         "cluster provider not restricted",
+			kind:         apiv1beta3.ClusterProviderKind,
+			providerType: apiv1beta3.GenericProvider,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			provider := &apiv1beta3.Provider{}
+			provider.Kind = tt.kind
+			provider.Name = "provider"
+			provider.Namespace = tt.namespace
+			provider.Spec.Type = tt.providerType
+
+			err := policy.Validate(context.Background(), kubeClient, provider)
+			if tt.wantErr {
+				g.Expect(err).To(MatchError(ErrProviderTypeNotAllowed))
+				g.Expect(err.Error()).To(ContainSubstring("type 'generic' is not allowed in namespace 'team-a'"))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+
+	var nilPolicy *ProviderTypePolicy
+	provider := &apiv1beta3.Provider{}
+	provider.Namespace = "team-a"
+	provider.Spec.Type = apiv1beta3.GenericProvider
+	NewWithT(t).Expect(nilPolicy.Validate(context.Background(), kubeClient, provider)).To(Succeed())
+}
+
+func TestLoadProviderTypePolicy_invalid(t *testing.T) {
+	for _, policy := range []string{
+		"rules:\n- allowedTypes: [slack]\n",
+		"rules:\n- namespaceSelector:\n    matchLabels:\n      'in valid': x\n  allowedTypes: [slack]\n",
+		"rule: []\n",
+	} {
+		_, err := loadTestProviderTypePolicy(t, policy)
+		NewWithT(t).Expect(err).To(HaveOccurred(), policy)
+	}
+}
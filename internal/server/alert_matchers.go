@@ -0,0 +1,115 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"regexp"
+	"slices"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+// alertMatcherCacheMaxSize is the maximum number of Alerts for which the
+// compiled matchers are kept.
+const alertMatcherCacheMaxSize = 10000
+
+// alertMatcher holds the compiled inclusion and exclusion lists of an Alert.
+type alertMatcher struct {
+	uid           types.UID
+	generation    int64
+	inclusionList []compiledRegexp
+	exclusionList []compiledRegexp
+}
+
+// compiledRegexp is a regular expression of an Alert along with the result
+// of its compilation.
+type compiledRegexp struct {
+	expr   string
+	regexp *regexp.Regexp
+	err    error
+}
+
+// alertMatcherCache holds the compiled matchers of the Alerts, so that the
+// regular expressions of an Alert are only compiled once per generation.
+type alertMatcherCache struct {
+	mu       sync.Mutex
+	matchers map[string]*alertMatcher
+}
+
+func newAlertMatcherCache() *alertMatcherCache {
+	return &alertMatcherCache{matchers: make(map[string]*alertMatcher)}
+}
+
+// get returns the compiled matcher of the given Alert. A nil cache compiles
+// the matcher on every call.
+func (c *alertMatcherCache) get(alert *apiv1beta3.Alert) *alertMatcher {
+	if c == nil {
+		return newAlertMatcher(alert)
+	}
+
+	key := alert.Kind + "/" + alert.Namespace + "/" + alert.Name
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if m, ok := c.matchers[key]; ok && m.matches(alert) {
+		return m
+	}
+
+	// Drop the matchers of all the Alerts when the cache is full, the
+	// matchers of the Alerts still in use are compiled again.
+	if len(c.matchers) >= alertMatcherCacheMaxSize {
+		clear(c.matchers)
+	}
+	m := newAlertMatcher(alert)
+	c.matchers[key] = m
+	return m
+}
+
+// matches returns if the matcher has been compiled for the given Alert.
+// The expressions are compared as well, for the Alerts without a
+// generation.
+func (m *alertMatcher) matches(alert *apiv1beta3.Alert) bool {
+	return m.uid == alert.UID && m.generation == alert.Generation &&
+		slices.EqualFunc(m.inclusionList, alert.Spec.InclusionList, compiledRegexpEqual) &&
+		slices.EqualFunc(m.exclusionList, alert.Spec.ExclusionList, compiledRegexpEqual)
+}
+
+func compiledRegexpEqual(r compiledRegexp, expr string) bool {
+	return r.expr == expr
+}
+
+func newAlertMatcher(alert *apiv1beta3.Alert) *alertMatcher {
+	return &alertMatcher{
+		uid:           alert.UID,
+		generation:    alert.Generation,
+		inclusionList: compileRegexps(alert.Spec.InclusionList),
+		exclusionList: compileRegexps(alert.Spec.ExclusionList),
+	}
+}
+
+func compileRegexps(exprs []string) []compiledRegexp {
+	compiled := make([]compiledRegexp, 0, len(exprs))
+	for _, expr := range exprs {
+		r, err := regexp.Compile(expr)
+		compiled = append(compiled, compiledRegexp{expr: expr, regexp: r, err: err})
+	}
+	return compiled
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestAlertMatcherCache(t *testing.T) {
+	g := NewWithT(t)
+
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert"
+	alert.Namespace = "foo-ns"
+	alert.UID = "alert-uid"
+	alert.Generation = 1
+	alert.Spec.InclusionList = []string{"^include.*"}
+	alert.Spec.ExclusionList = []string{"["}
+
+	cache := newAlertMatcherCache()
+
+	m1 := cache.get(alert)
+	g.Expect(m1.inclusionList).To(HaveLen(1))
+	g.Expect(m1.inclusionList[0].err).ToNot(HaveOccurred())
+	g.Expect(m1.inclusionList[0].regexp.MatchString("include me")).To(BeTrue())
+	g.Expect(m1.exclusionList).To(HaveLen(1))
+	g.Expect(m1.exclusionList[0].err).To(HaveOccurred())
+
+	g.Expect(cache.get(alert)).To(BeIdenticalTo(m1), "matcher reused for the same generation")
+
+	alert.Generation = 2
+	alert.Spec.ExclusionList = []string{"exclude"}
+	m2 := cache.get(alert)
+	g.Expect(m2).ToNot(BeIdenticalTo(m1), "matcher recompiled for a new generation")
+	g.Expect(m2.exclusionList[0].err).ToNot(HaveOccurred())
+
+	recreated := alert.DeepCopy()
+	recreated.UID = "other-uid"
+	g.Expect(cache.get(recreated)).ToNot(BeIdenticalTo(m2), "matcher recompiled for a recreated alert")
+
+	clusterAlert := alert.DeepCopy()
+	clusterAlert.Kind = apiv1beta3.ClusterAlertKind
+	clusterAlert.Spec.ExclusionList = nil
+	g.Expect(cache.get(clusterAlert).exclusionList).To(BeEmpty())
+
+	var nilCache *alertMatcherCache
+	g.Expect(nilCache.get(alert).exclusionList).To(HaveLen(1))
+}
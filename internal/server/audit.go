@@ -0,0 +1,284 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+// Outcomes of the audited notifications.
+const (
+	auditOutcomeSent    = "sent"
+	auditOutcomeFailed  = "failed"
+	auditOutcomeSkipped = "skipped"
+)
+
+// auditReason is the reason of the events sent to an audit Provider.
+const auditReason = "NotificationAudit"
+
+// auditQueueSize is the maximum number of records waiting to be sent to an
+// audit Provider.
+const auditQueueSize = 1024
+
+// errAuditQueueFull is returned when a record can't be queued for the audit
+// Provider.
+var errAuditQueueFull = errors.New("audit provider queue is full")
+
+// AuditRecord is the record of a notification sent to a Provider.
+type AuditRecord struct {
+	// Time is when the notification was sent.
+	Time time.Time `json:"time"`
+	// Event identifies the event from which the notification was sent.
+	Event AuditEvent `json:"event"`
+	// Alert is the Alert or ClusterAlert that matched the event.
+	Alert AuditObject `json:"alert"`
+	// Provider is the Provider or ClusterProvider of the notification.
+	Provider AuditProvider `json:"provider"`
+	// CommitStatus is the commit status ID, for the git Providers.
+	CommitStatus string `json:"commitStatus,omitempty"`
+	// Outcome is either sent, failed or skipped.
+	Outcome string `json:"outcome"`
+	// LatencyMillis is the duration of the request to the Provider.
+	LatencyMillis int64 `json:"latencyMillis"`
+	// Error is the error of the Provider, with its secret values masked.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditEvent identifies an event.
+type AuditEvent struct {
+	AuditObject `json:"involvedObject"`
+	Reason      string    `json:"reason"`
+	Severity    string    `json:"severity"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// AuditObject identifies a Kubernetes object.
+type AuditObject struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// AuditProvider identifies a Provider and its type.
+type AuditProvider struct {
+	AuditObject
+	Type string `json:"type,omitempty"`
+}
+
+// AuditSink records the notifications sent to the Providers.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// writerAuditSink writes the records as JSON lines.
+type writerAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterAuditSink returns an AuditSink writing the records as JSON lines
+// to the given writer.
+func NewWriterAuditSink(w io.Writer) AuditSink {
+	return &writerAuditSink{w: w}
+}
+
+func (s *writerAuditSink) Record(_ context.Context, record AuditRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
+
+// providerAuditSink sends the records to a Provider, as events with the
+// record in their message. The records are queued and sent one after the
+// other in the background, so that a slow audit Provider doesn't delay the
+// notifications. The records are dropped when the queue is full.
+type providerAuditSink struct {
+	server   *EventServer
+	provider types.NamespacedName
+	records  chan AuditRecord
+	start    sync.Once
+}
+
+func newProviderAuditSink(server *EventServer, provider types.NamespacedName, queueSize int) *providerAuditSink {
+	return &providerAuditSink{
+		server:   server,
+		provider: provider,
+		records:  make(chan AuditRecord, queueSize),
+	}
+}
+
+// Record queues the given record, returning errAuditQueueFull when the
+// queue is full.
+func (s *providerAuditSink) Record(_ context.Context, record AuditRecord) error {
+	s.start.Do(func() { go s.run() })
+	select {
+	case s.records <- record:
+		return nil
+	default:
+		s.server.metrics.recordAuditDropped()
+		return errAuditQueueFull
+	}
+}
+
+// run sends the queued records to the audit Provider.
+func (s *providerAuditSink) run() {
+	for record := range s.records {
+		if err := s.send(context.Background(), record); err != nil {
+			s.server.logger.Error(err, "failed to send notification audit", "provider", s.provider.String())
+		}
+	}
+}
+
+func (s *providerAuditSink) send(ctx context.Context, record AuditRecord) error {
+	var provider apiv1beta3.Provider
+	if err := s.server.kubeClient.Get(ctx, s.provider, &provider); err != nil {
+		return fmt.Errorf("failed to read audit provider: %w", err)
+	}
+	sender, masker, err := s.server.getNotifier(ctx, &provider, "")
+	if err != nil {
+		return fmt.Errorf("failed to initialize notifier for audit provider '%s': %w", provider.Name, err)
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	event := eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      record.Event.Kind,
+			Namespace: record.Event.Namespace,
+			Name:      record.Event.Name,
+		},
+		Severity:            eventv1.EventSeverityInfo,
+		Timestamp:           metav1.NewTime(record.Event.Timestamp),
+		Message:             string(b),
+		Reason:              auditReason,
+		ReportingController: "notification-controller",
+	}
+	return postNotification(ctx, sender, event, masker, provider.GetTimeout())
+}
+
+// WithAuditSink records the notifications sent to the Providers in the audit
+// sink described by the given spec, either stdout, file:<path> or
+// provider:<namespace>/<name>.
+func WithAuditSink(spec string) (EventServerOption, error) {
+	switch {
+	case spec == "stdout":
+		sink := NewWriterAuditSink(os.Stdout)
+		return func(s *EventServer) { s.auditSink = sink }, nil
+	case strings.HasPrefix(spec, "file:"):
+		path := strings.TrimPrefix(spec, "file:")
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit file: %w", err)
+		}
+		sink := NewWriterAuditSink(f)
+		return func(s *EventServer) { s.auditSink = sink }, nil
+	case strings.HasPrefix(spec, "provider:"):
+		namespace, name, ok := strings.Cut(strings.TrimPrefix(spec, "provider:"), "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("invalid audit provider '%s', expected provider:<namespace>/<name>", spec)
+		}
+		return func(s *EventServer) {
+			s.auditSink = newProviderAuditSink(s, types.NamespacedName{Namespace: namespace, Name: name}, auditQueueSize)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid audit sink '%s', expected stdout, file:<path> or provider:<namespace>/<name>", spec)
+	}
+}
+
+// recordAudit records the notification sent to the Provider of the given
+// alert in the audit sink, if any. The given parameters are nil, or hold the
+// Provider only, when the notification failed before being sent.
+func (s *EventServer) recordAudit(ctx context.Context, event *eventv1.Event, alert *apiv1beta3.Alert,
+	params *notificationParams, latency time.Duration, sendErr error) {
+	if s.auditSink == nil {
+		return
+	}
+	if params == nil {
+		params = &notificationParams{}
+	}
+
+	provider := providerReferenceFor(alert)
+	record := AuditRecord{
+		Time: time.Now().UTC(),
+		Event: AuditEvent{
+			AuditObject: AuditObject{
+				Kind:      event.InvolvedObject.Kind,
+				Namespace: event.InvolvedObject.Namespace,
+				Name:      event.InvolvedObject.Name,
+			},
+			Reason:    event.Reason,
+			Severity:  event.Severity,
+			Timestamp: event.Timestamp.Time,
+		},
+		Alert: AuditObject{
+			Kind:      alert.Kind,
+			Namespace: alert.Namespace,
+			Name:      alert.Name,
+		},
+		Provider: AuditProvider{
+			AuditObject: AuditObject{
+				Kind:      provider.Kind,
+				Namespace: provider.Namespace,
+				Name:      provider.Name,
+			},
+		},
+		CommitStatus:  params.commitStatus,
+		Outcome:       auditOutcomeSent,
+		LatencyMillis: latency.Milliseconds(),
+	}
+	if record.Alert.Kind == "" {
+		record.Alert.Kind = apiv1beta3.AlertKind
+	}
+	if params.provider != nil {
+		record.Provider.Type = params.provider.Spec.Type
+	}
+	switch {
+	case errors.Is(sendErr, errCircuitOpen):
+		record.Outcome = auditOutcomeSkipped
+		record.Error = sendErr.Error()
+	case sendErr != nil:
+		record.Outcome = auditOutcomeFailed
+		record.Error = params.masker.mask(sendErr.Error())
+	}
+
+	if err := s.auditSink.Record(ctx, record); err != nil {
+		s.logger.Error(err, "failed to record notification audit", "provider", record.Provider.Name)
+	}
+}
@@ -0,0 +1,311 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestRecordAudit(t *testing.T) {
+	event := &eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Kustomization",
+			Namespace: "foo-ns",
+			Name:      "app",
+		},
+		Severity: eventv1.EventSeverityError,
+		Reason:   "ReconciliationFailed",
+	}
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert"
+	alert.Namespace = "foo-ns"
+	alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: "github"}
+	provider := &apiv1beta3.Provider{}
+	provider.Spec.Type = apiv1beta3.GitHubProvider
+
+	tests := []struct {
+		name        string
+		err         error
+		wantOutcome string
+		wantError   string
+	}{
+		{
+			name:        "sent",
+			wantOutcome: auditOutcomeSent,
+		},
+		{
+			name:        "failed with masked token",
+			err:         errors.New("unauthorized token s3cr3t"),
+			wantOutcome: auditOutcomeFailed,
+			wantError:   "unauthorized token *****",
+		},
+		{
+			name:        "skipped",
+			err:         errCircuitOpen,
+			wantOutcome: auditOutcomeSkipped,
+			wantError:   errCircuitOpen.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var buf bytes.Buffer
+			s := &EventServer{auditSink: NewWriterAuditSink(&buf)}
+			params := &notificationParams{provider: provider, masker: newSecretMasker(&corev1.Secret{Data: map[string][]byte{"token": []byte("s3cr3t")}}), commitStatus: "flux/app"}
+			s.recordAudit(context.Background(), event, alert, params, 1500*time.Millisecond, tt.err)
+
+			var record AuditRecord
+			g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+			g.Expect(buf.String()).To(HaveSuffix("}\n"))
+			g.Expect(record.Event.Kind).To(Equal("Kustomization"))
+			g.Expect(record.Event.Reason).To(Equal("ReconciliationFailed"))
+			g.Expect(record.Alert).To(Equal(AuditObject{Kind: apiv1beta3.AlertKind, Namespace: "foo-ns", Name: "alert"}))
+			g.Expect(record.Provider.Kind).To(Equal(apiv1beta3.ProviderKind))
+			g.Expect(record.Provider.Name).To(Equal("github"))
+			g.Expect(record.Provider.Type).To(Equal(apiv1beta3.GitHubProvider))
+			g.Expect(record.CommitStatus).To(Equal("flux/app"))
+			g.Expect(record.LatencyMillis).To(Equal(int64(1500)))
+			g.Expect(record.Outcome).To(Equal(tt.wantOutcome))
+			g.Expect(record.Error).To(Equal(tt.wantError))
+		})
+	}
+}
+
+func TestDispatchNotification_auditFailures(t *testing.T) {
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "provider"
+	provider.Namespace = "foo-ns"
+	provider.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: "https://example.com"}
+	noAddressProvider := provider.DeepCopy()
+	noAddressProvider.Spec.Address = ""
+
+	tests := []struct {
+		name                 string
+		provider             *apiv1beta3.Provider
+		noCrossNamespaceRefs bool
+		queueFull            bool
+		eventNamespace       string
+		wantError            string
+		wantType             string
+	}{
+		{
+			name:      "missing provider",
+			wantError: "failed to read provider",
+		},
+		{
+			name:                 "cross-namespace event",
+			provider:             provider,
+			noCrossNamespaceRefs: true,
+			eventNamespace:       "bar-ns",
+			wantError:            "cross-namespace references have been blocked",
+		},
+		{
+			name:      "notifier initialization failure",
+			provider:  noAddressProvider,
+			wantError: "provider has no address",
+			wantType:  apiv1beta3.GenericProvider,
+		},
+		{
+			name:      "dispatch queue full",
+			provider:  provider,
+			queueFull: true,
+			wantError: errDispatchQueueFull.Error(),
+			wantType:  apiv1beta3.GenericProvider,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			alert := &apiv1beta3.Alert{}
+			alert.Name = "alert"
+			alert.Namespace = "foo-ns"
+			alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: "provider"}
+			event := &eventv1.Event{InvolvedObject: corev1.ObjectReference{
+				Kind:      "Kustomization",
+				Namespace: "foo-ns",
+				Name:      "app",
+			}}
+			if tt.eventNamespace != "" {
+				event.InvolvedObject.Namespace = tt.eventNamespace
+			}
+
+			scheme := runtime.NewScheme()
+			g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+			builder := fakeclient.NewClientBuilder().WithScheme(scheme)
+			if tt.provider != nil {
+				builder = builder.WithObjects(tt.provider.DeepCopy())
+			}
+			var buf bytes.Buffer
+			s := &EventServer{
+				kubeClient:           builder.Build(),
+				logger:               log.Log,
+				noCrossNamespaceRefs: tt.noCrossNamespaceRefs,
+				auditSink:            NewWriterAuditSink(&buf),
+			}
+			if tt.queueFull {
+				s.dispatcher = newDispatcher(DispatcherOptions{QueueSize: 1})
+				s.dispatcher.queued = 1
+			}
+
+			g.Expect(s.dispatchNotification(context.Background(), event, alert)).ToNot(Succeed())
+
+			var record AuditRecord
+			g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+			g.Expect(record.Alert).To(Equal(AuditObject{Kind: apiv1beta3.AlertKind, Namespace: "foo-ns", Name: "alert"}))
+			g.Expect(record.Provider.Name).To(Equal("provider"))
+			g.Expect(record.Provider.Type).To(Equal(tt.wantType))
+			g.Expect(record.Outcome).To(Equal(auditOutcomeFailed))
+			g.Expect(record.Error).To(ContainSubstring(tt.wantError))
+		})
+	}
+}
+
+func TestWithAuditSink(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, spec := range []string{"", "stderr", "provider:foo", "provider:/foo", "file:" + t.TempDir()} {
+		_, err := WithAuditSink(spec)
+		g.Expect(err).To(HaveOccurred(), spec)
+	}
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	opt, err := WithAuditSink("file:" + path)
+	g.Expect(err).ToNot(HaveOccurred())
+	s := &EventServer{}
+	opt(s)
+	for range 2 {
+		g.Expect(s.auditSink.Record(context.Background(), AuditRecord{Outcome: auditOutcomeSent})).To(Succeed())
+	}
+	f, err := os.Open(path)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		g.Expect(scanner.Text()).To(ContainSubstring(`"outcome":"sent"`))
+	}
+	g.Expect(lines).To(Equal(2))
+}
+
+func TestProviderAuditSink(t *testing.T) {
+	g := NewWithT(t)
+
+	received := make(chan eventv1.Event, 1)
+	rcvServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event eventv1.Event
+		g.Expect(json.Unmarshal(body, &event)).To(Succeed())
+		received <- event
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer rcvServer.Close()
+
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "audit"
+	provider.Namespace = "flux-system"
+	provider.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: rcvServer.URL}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+	s := &EventServer{
+		kubeClient: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(provider).Build(),
+		logger:     log.Log,
+	}
+	opt, err := WithAuditSink("provider:flux-system/audit")
+	g.Expect(err).ToNot(HaveOccurred())
+	opt(s)
+
+	record := AuditRecord{Outcome: auditOutcomeFailed, Error: "failed"}
+	record.Event.Kind = "Kustomization"
+	record.Event.Name = "app"
+	g.Expect(s.auditSink.Record(context.Background(), record)).To(Succeed())
+
+	var event eventv1.Event
+	g.Eventually(received).Should(Receive(&event))
+	g.Expect(event.Reason).To(Equal(auditReason))
+	g.Expect(event.InvolvedObject.Name).To(Equal("app"))
+	var sent AuditRecord
+	g.Expect(json.Unmarshal([]byte(event.Message), &sent)).To(Succeed())
+	g.Expect(sent.Outcome).To(Equal(auditOutcomeFailed))
+}
+
+func TestProviderAuditSink_queueFull(t *testing.T) {
+	g := NewWithT(t)
+
+	received := make(chan struct{})
+	release := make(chan struct{})
+	rcvServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer rcvServer.Close()
+	defer close(release)
+
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "audit"
+	provider.Namespace = "flux-system"
+	provider.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: rcvServer.URL}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+	s := &EventServer{
+		kubeClient: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(provider).Build(),
+		logger:     log.Log,
+	}
+	WithMetricsRegisterer(prometheus.NewRegistry())(s)
+	sink := newProviderAuditSink(s, types.NamespacedName{Namespace: "flux-system", Name: "audit"}, 1)
+
+	// The first record is being sent, the second one waits in the queue.
+	g.Expect(sink.Record(context.Background(), AuditRecord{Outcome: auditOutcomeSent})).To(Succeed())
+	g.Eventually(received).Should(Receive())
+	g.Expect(sink.Record(context.Background(), AuditRecord{Outcome: auditOutcomeSent})).To(Succeed())
+
+	g.Expect(sink.Record(context.Background(), AuditRecord{Outcome: auditOutcomeSent})).To(MatchError(errAuditQueueFull))
+	g.Expect(testutil.ToFloat64(s.metrics.auditDroppedTotal)).To(Equal(float64(1)))
+
+	release <- struct{}{}
+	g.Eventually(received).Should(Receive())
+}
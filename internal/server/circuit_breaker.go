@@ -0,0 +1,257 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+)
+
+// circuitState is the state of the circuit breaker of a Provider.
+type circuitState int
+
+const (
+	// circuitClosed lets the notifications through.
+	circuitClosed circuitState = iota
+	// circuitHalfOpen lets a single notification through, probing the
+	// Provider.
+	circuitHalfOpen
+	// circuitOpen skips the notifications.
+	circuitOpen
+)
+
+func (s circuitState) String() string {
+	switch s {
+	case circuitHalfOpen:
+		return "half-open"
+	case circuitOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// errCircuitOpen is returned for the notifications skipped while the circuit
+// breaker of their Provider is open.
+var errCircuitOpen = errors.New("circuit breaker is open after consecutive failures, notification skipped")
+
+// CircuitBreakerOptions configures the circuit breakers of the Providers.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures after which
+	// the circuit breaker of a Provider opens.
+	FailureThreshold int
+	// OpenTimeout is the duration for which the circuit breaker of a
+	// Provider stays open, before letting a notification through to probe
+	// the Provider.
+	OpenTimeout time.Duration
+}
+
+// isProviderFailure returns if the given error of a notification means that
+// the Provider is unavailable: a transport error, a timeout, or a 429 or 5xx
+// HTTP response. The other errors, e.g. a 4xx HTTP response caused by an
+// invalid notification, don't count towards opening the circuit breaker.
+func isProviderFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *notifier.StatusError
+	if errors.As(err, &statusErr) {
+		return isUnavailableStatus(statusErr.StatusCode)
+	}
+	// The errors of the AWS SDK.
+	var responseErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &responseErr) {
+		return isUnavailableStatus(responseErr.HTTPStatusCode())
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
+
+func isUnavailableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// providerCircuit is the circuit breaker of a Provider.
+type providerCircuit struct {
+	state    circuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// circuitBreakers holds the circuit breakers of the Providers. A nil
+// *circuitBreakers lets all the notifications through.
+type circuitBreakers struct {
+	opts CircuitBreakerOptions
+	// onStateChange is called with the new state of the circuit breaker of
+	// a Provider, outside the lock. The Provider object is the one read
+	// for the notification which changed the state.
+	onStateChange func(provider providerReference, obj *apiv1beta3.Provider, state circuitState)
+
+	mu       sync.Mutex
+	circuits map[providerReference]*providerCircuit
+}
+
+func newCircuitBreakers(opts CircuitBreakerOptions, onStateChange func(providerReference, *apiv1beta3.Provider, circuitState)) *circuitBreakers {
+	opts.FailureThreshold = max(opts.FailureThreshold, 1)
+	return &circuitBreakers{
+		opts:          opts,
+		onStateChange: onStateChange,
+		circuits:      make(map[providerReference]*providerCircuit),
+	}
+}
+
+// allow returns errCircuitOpen if a notification can't be sent to the given
+// Provider. Once the open timeout has elapsed, a single notification is
+// allowed through to probe the Provider, and its result must be recorded.
+func (b *circuitBreakers) allow(provider providerReference, obj *apiv1beta3.Provider) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	c, ok := b.circuits[provider]
+	if !ok || c.state == circuitClosed {
+		b.mu.Unlock()
+		return nil
+	}
+	halfOpened := false
+	if c.state == circuitOpen && time.Since(c.openedAt) >= b.opts.OpenTimeout {
+		c.state = circuitHalfOpen
+		c.probing = false
+		halfOpened = true
+	}
+	var err error
+	if c.state == circuitHalfOpen && !c.probing {
+		c.probing = true
+	} else {
+		err = errCircuitOpen
+	}
+	b.mu.Unlock()
+
+	if halfOpened {
+		b.stateChanged(provider, obj, circuitHalfOpen)
+	}
+	return err
+}
+
+// record records the result of sending a notification to the given
+// Provider. The errors which are not a Provider failure are recorded as a
+// success, as the Provider is available.
+func (b *circuitBreakers) record(provider providerReference, obj *apiv1beta3.Provider, err error) {
+	if b == nil || errors.Is(err, errCircuitOpen) {
+		return
+	}
+
+	b.mu.Lock()
+	c, ok := b.circuits[provider]
+	if !isProviderFailure(err) {
+		if !ok {
+			b.mu.Unlock()
+			return
+		}
+		prev := c.state
+		delete(b.circuits, provider)
+		b.mu.Unlock()
+		if prev != circuitClosed {
+			b.stateChanged(provider, obj, circuitClosed)
+		}
+		return
+	}
+
+	if !ok {
+		c = &providerCircuit{}
+		b.circuits[provider] = c
+	}
+	c.failures++
+	opened := false
+	if c.state == circuitHalfOpen || (c.state == circuitClosed && c.failures >= b.opts.FailureThreshold) {
+		c.state = circuitOpen
+		c.openedAt = time.Now()
+		c.probing = false
+		opened = true
+	}
+	b.mu.Unlock()
+	if opened {
+		b.stateChanged(provider, obj, circuitOpen)
+	}
+}
+
+// state returns the state of the circuit breaker of the given Provider.
+func (b *circuitBreakers) state(provider providerReference) circuitState {
+	if b == nil {
+		return circuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[provider]; ok {
+		return c.state
+	}
+	return circuitClosed
+}
+
+func (b *circuitBreakers) stateChanged(provider providerReference, obj *apiv1beta3.Provider, state circuitState) {
+	if b.onStateChange != nil {
+		b.onStateChange(provider, obj, state)
+	}
+}
+
+// circuitStateChanged records the new state of the circuit breaker of the
+// given Provider in the metrics, the logs and the Kubernetes events of the
+// given Provider object.
+func (s *EventServer) circuitStateChanged(provider providerReference, obj *apiv1beta3.Provider, state circuitState) {
+	s.metrics.recordCircuitState(provider, state)
+
+	logger := s.logger.WithValues(provider.Kind, provider.Name, "namespace", provider.Namespace)
+	var eventObj runtime.Object
+	if obj != nil && s.EventRecorder != nil {
+		eventObj = obj
+		if provider.Kind == apiv1beta3.ClusterProviderKind {
+			// The ClusterProviders are read as Providers in the namespace of
+			// the cluster resources.
+			clusterProvider := &apiv1beta3.ClusterProvider{ObjectMeta: *obj.ObjectMeta.DeepCopy()}
+			clusterProvider.Namespace = ""
+			eventObj = clusterProvider
+		}
+	}
+
+	switch state {
+	case circuitOpen:
+		logger.Info("circuit breaker opened, skipping notifications", "timeout", s.circuitBreakers.opts.OpenTimeout.String())
+		if eventObj != nil {
+			s.Eventf(eventObj, corev1.EventTypeWarning, "CircuitBreakerOpen",
+				"notifications are skipped for %s after consecutive failures", s.circuitBreakers.opts.OpenTimeout)
+		}
+	case circuitHalfOpen:
+		logger.Info("circuit breaker half-open, probing provider")
+	case circuitClosed:
+		logger.Info("circuit breaker closed, provider recovered")
+		if eventObj != nil {
+			s.Eventf(eventObj, corev1.EventTypeNormal, "CircuitBreakerClosed", "notifications are sent again")
+		}
+	}
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+)
+
+// failingNotifier fails every notification with err.
+type failingNotifier struct {
+	err   error
+	calls int
+}
+
+func (n *failingNotifier) Post(context.Context, eventv1.Event) error {
+	n.calls++
+	return n.err
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	g := NewWithT(t)
+
+	var transitions []circuitState
+	b := newCircuitBreakers(CircuitBreakerOptions{
+		FailureThreshold: 3,
+		OpenTimeout:      50 * time.Millisecond,
+	}, func(_ providerReference, _ *apiv1beta3.Provider, state circuitState) {
+		transitions = append(transitions, state)
+	})
+
+	provider := providerReference{Kind: apiv1beta3.ProviderKind, Namespace: "foo-ns", Name: "foo"}
+	other := providerReference{Kind: apiv1beta3.ProviderKind, Namespace: "foo-ns", Name: "bar"}
+	errFailed := &notifier.StatusError{StatusCode: http.StatusServiceUnavailable, Err: errors.New("failed")}
+
+	// A success resets the consecutive failures.
+	b.record(provider, nil, errFailed)
+	b.record(provider, nil, errFailed)
+	b.record(provider, nil, nil)
+	b.record(provider, nil, errFailed)
+	b.record(provider, nil, errFailed)
+	g.Expect(b.state(provider)).To(Equal(circuitClosed))
+	g.Expect(b.allow(provider, nil)).To(Succeed())
+
+	b.record(provider, nil, errFailed)
+	g.Expect(b.state(provider)).To(Equal(circuitOpen))
+	g.Expect(b.allow(provider, nil)).To(MatchError(errCircuitOpen))
+	g.Expect(b.allow(other, nil)).To(Succeed())
+
+	// A single probe is allowed after the open timeout, and a failed probe
+	// opens the circuit again.
+	time.Sleep(50 * time.Millisecond)
+	g.Expect(b.allow(provider, nil)).To(Succeed())
+	g.Expect(b.state(provider)).To(Equal(circuitHalfOpen))
+	g.Expect(b.allow(provider, nil)).To(MatchError(errCircuitOpen))
+	b.record(provider, nil, errFailed)
+	g.Expect(b.state(provider)).To(Equal(circuitOpen))
+	g.Expect(b.allow(provider, nil)).To(MatchError(errCircuitOpen))
+
+	// A successful probe closes the circuit.
+	time.Sleep(50 * time.Millisecond)
+	g.Expect(b.allow(provider, nil)).To(Succeed())
+	b.record(provider, nil, nil)
+	g.Expect(b.state(provider)).To(Equal(circuitClosed))
+	g.Expect(b.allow(provider, nil)).To(Succeed())
+
+	g.Expect(transitions).To(Equal([]circuitState{
+		circuitOpen, circuitHalfOpen, circuitOpen, circuitHalfOpen, circuitClosed,
+	}))
+
+	// The errors which don't mean that the Provider is unavailable don't
+	// count towards opening the circuit.
+	for range 3 {
+		b.record(provider, nil, &notifier.StatusError{StatusCode: http.StatusBadRequest, Err: errors.New("bad request")})
+	}
+	g.Expect(b.state(provider)).To(Equal(circuitClosed))
+
+	var nilBreakers *circuitBreakers
+	g.Expect(nilBreakers.allow(provider, nil)).To(Succeed())
+}
+
+func TestSendProviderNotification_circuitBreaker(t *testing.T) {
+	g := NewWithT(t)
+
+	reg := prometheus.NewRegistry()
+	recorder := &objectRecorder{}
+	s := &EventServer{
+		logger:        log.Log,
+		EventRecorder: recorder,
+	}
+	WithMetricsRegisterer(reg)(s)
+	WithCircuitBreakerOptions(CircuitBreakerOptions{FailureThreshold: 2, OpenTimeout: time.Hour})(s)
+
+	alert := &apiv1beta3.Alert{}
+	alert.Namespace = "foo-ns"
+	alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: "foo"}
+	provider := providerReferenceFor(alert)
+
+	errRefused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	n := &failingNotifier{err: errRefused}
+	params := &notificationParams{
+		sender:       n,
+		notification: &eventv1.Event{},
+		timeout:      time.Second,
+		provider:     &apiv1beta3.Provider{},
+	}
+	params.provider.Name = "foo"
+	params.provider.Namespace = "foo-ns"
+	params.provider.UID = "7c1d4bd2-6ad1-4c4b-9c8e-3c4b1c5a2f10"
+	for range 2 {
+		g.Expect(s.sendProviderNotification(context.Background(), params.notification, alert, params)).
+			To(MatchError(errRefused))
+	}
+	g.Expect(s.sendProviderNotification(context.Background(), params.notification, alert, params)).
+		To(MatchError(errCircuitOpen))
+	g.Expect(n.calls).To(Equal(2))
+
+	g.Expect(testutil.ToFloat64(s.metrics.circuitState.WithLabelValues(provider.Kind, provider.Namespace, provider.Name))).
+		To(Equal(float64(circuitOpen)))
+	g.Expect(testutil.ToFloat64(s.metrics.circuitSkippedTotal.WithLabelValues(provider.Kind, provider.Namespace, provider.Name))).
+		To(Equal(float64(1)))
+	g.Expect(recorder.events).To(HaveLen(1))
+	g.Expect(recorder.events[0].reason).To(Equal("CircuitBreakerOpen"))
+	// The event is recorded on the Provider read from the API server.
+	g.Expect(recorder.events[0].object).To(BeIdenticalTo(params.provider))
+}
+
+func TestSendProviderNotification_circuitBreakerClusterProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := &objectRecorder{}
+	s := &EventServer{
+		logger:        log.Log,
+		EventRecorder: recorder,
+	}
+	WithMetricsRegisterer(prometheus.NewRegistry())(s)
+	WithCircuitBreakerOptions(CircuitBreakerOptions{FailureThreshold: 1, OpenTimeout: time.Hour})(s)
+
+	alert := &apiv1beta3.Alert{}
+	alert.Kind = apiv1beta3.ClusterAlertKind
+	alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: "foo"}
+
+	provider := &apiv1beta3.Provider{}
+	provider.Kind = apiv1beta3.ClusterProviderKind
+	provider.Name = "foo"
+	provider.Namespace = "flux-system"
+	provider.UID = "7c1d4bd2-6ad1-4c4b-9c8e-3c4b1c5a2f10"
+	params := &notificationParams{
+		sender:       &failingNotifier{err: context.DeadlineExceeded},
+		notification: &eventv1.Event{},
+		timeout:      time.Second,
+		provider:     provider,
+	}
+	g.Expect(s.sendProviderNotification(context.Background(), params.notification, alert, params)).
+		To(MatchError(context.DeadlineExceeded))
+
+	g.Expect(recorder.events).To(HaveLen(1))
+	clusterProvider, ok := recorder.events[0].object.(*apiv1beta3.ClusterProvider)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(clusterProvider.Name).To(Equal("foo"))
+	g.Expect(clusterProvider.Namespace).To(BeEmpty())
+	g.Expect(clusterProvider.UID).To(Equal(provider.UID))
+}
+
+func TestSendProviderNotification_circuitBreakerClientError(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := &objectRecorder{}
+	s := &EventServer{
+		logger:        log.Log,
+		EventRecorder: recorder,
+	}
+	WithMetricsRegisterer(prometheus.NewRegistry())(s)
+	WithCircuitBreakerOptions(CircuitBreakerOptions{FailureThreshold: 1, OpenTimeout: time.Hour})(s)
+
+	alert := &apiv1beta3.Alert{}
+	alert.Namespace = "foo-ns"
+	alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: "foo"}
+
+	// A 4xx response is caused by the notification, not by the Provider
+	// being unavailable.
+	errBadRequest := &notifier.StatusError{StatusCode: http.StatusBadRequest, Err: errors.New("request failed with status code 400")}
+	n := &failingNotifier{err: errBadRequest}
+	params := &notificationParams{
+		sender:       n,
+		notification: &eventv1.Event{},
+		timeout:      time.Second,
+		provider:     &apiv1beta3.Provider{},
+	}
+	for range 3 {
+		g.Expect(s.sendProviderNotification(context.Background(), params.notification, alert, params)).
+			To(MatchError(errBadRequest))
+	}
+	g.Expect(n.calls).To(Equal(3))
+	g.Expect(s.circuitBreakers.state(providerReferenceFor(alert))).To(Equal(circuitClosed))
+	g.Expect(recorder.events).To(BeEmpty())
+}
+
+func Test_isProviderFailure(t *testing.T) {
+	for name, tt := range map[string]struct {
+		err  error
+		want bool
+	}{
+		"nil":               {err: nil, want: false},
+		"other error":       {err: errors.New("invalid payload"), want: false},
+		"timeout":           {err: fmt.Errorf("post: %w", context.DeadlineExceeded), want: true},
+		"transport error":   {err: &url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("EOF")}, want: true},
+		"429 response":      {err: &notifier.RetryAfterError{Err: &notifier.StatusError{StatusCode: http.StatusTooManyRequests, Err: errors.New("429")}}, want: true},
+		"5xx response":      {err: &notifier.StatusError{StatusCode: http.StatusBadGateway, Err: errors.New("502")}, want: true},
+		"4xx response":      {err: &notifier.StatusError{StatusCode: http.StatusNotFound, Err: errors.New("404")}, want: false},
+		"SDK 5xx response":  {err: httpStatusCodeError(http.StatusInternalServerError), want: true},
+		"SDK 4xx response":  {err: httpStatusCodeError(http.StatusForbidden), want: false},
+		"masked 5xx errors": {err: &maskedError{msg: "500", err: &notifier.StatusError{StatusCode: 500, Err: errors.New("500")}}, want: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isProviderFailure(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
+
+// httpStatusCodeError is an error with an HTTP status code, like the errors
+// of the AWS SDK.
+type httpStatusCodeError int
+
+func (e httpStatusCodeError) Error() string {
+	return fmt.Sprintf("status code %d", int(e))
+}
+
+func (e httpStatusCodeError) HTTPStatusCode() int {
+	return int(e)
+}
+
+// recordedEvent is a Kubernetes event recorded by objectRecorder.
+type recordedEvent struct {
+	object runtime.Object
+	reason string
+}
+
+// objectRecorder records the Kubernetes events with their object.
+type objectRecorder struct {
+	record.FakeRecorder
+	events []recordedEvent
+}
+
+func (r *objectRecorder) Eventf(object runtime.Object, _, reason, _ string, _ ...interface{}) {
+	r.events = append(r.events, recordedEvent{object: object, reason: reason})
+}
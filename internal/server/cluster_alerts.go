@@ -0,0 +1,155 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1"
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/index"
+)
+
+// getClusterAlertsForEvent returns the ClusterAlerts with event sources of
+// the involved object kind that apply to the involved object namespace,
+// converted to Alerts scoped to that namespace.
+func (s *EventServer) getClusterAlertsForEvent(ctx context.Context, event *eventv1.Event) ([]apiv1beta3.Alert, error) {
+	namespace := event.InvolvedObject.Namespace
+
+	var clusterAlerts apiv1beta3.ClusterAlertList
+	if err := s.kubeClient.List(ctx, &clusterAlerts, client.MatchingFields{
+		index.ClusterAlertEventSourceKey: event.InvolvedObject.Kind,
+	}); err != nil {
+		return nil, fmt.Errorf("failed listing cluster alerts: %w", err)
+	}
+
+	var nsLabels labels.Set
+	alerts := make([]apiv1beta3.Alert, 0, len(clusterAlerts.Items))
+	for i := range clusterAlerts.Items {
+		clusterAlert := &clusterAlerts.Items[i]
+
+		if sel := clusterAlert.Spec.NamespaceSelector; sel != nil {
+			selector, err := metav1.LabelSelectorAsSelector(sel)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "error using namespaceSelector", apiv1beta3.ClusterAlertKind, clusterAlert.Name)
+				s.Eventf(clusterAlertToAlert(clusterAlert, ""), corev1.EventTypeWarning,
+					"InvalidConfig", "error using namespaceSelector: %s", err)
+				continue
+			}
+
+			if !selector.Empty() && nsLabels == nil {
+				var ns corev1.Namespace
+				if err := s.kubeClient.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+					return alerts, fmt.Errorf("failed to read namespace '%s': %w", namespace, err)
+				}
+				nsLabels = labels.Set(ns.GetLabels())
+			}
+
+			if !selector.Matches(nsLabels) {
+				continue
+			}
+		}
+
+		alerts = append(alerts, *clusterAlertToAlert(clusterAlert, namespace))
+	}
+	return alerts, nil
+}
+
+// clusterAlertToAlert converts the given ClusterAlert to an Alert, with the
+// event sources without a namespace scoped to the given namespace. The Alert
+// keeps the kind and metadata of the ClusterAlert, so that it can be told
+// apart from namespaced Alerts and used for recording Kubernetes events.
+func clusterAlertToAlert(clusterAlert *apiv1beta3.ClusterAlert, namespace string) *apiv1beta3.Alert {
+	eventSources := make([]apiv1.CrossNamespaceObjectReference, 0, len(clusterAlert.Spec.EventSources))
+	for _, source := range clusterAlert.Spec.EventSources {
+		if source.Namespace == "" {
+			source.Namespace = namespace
+		}
+		eventSources = append(eventSources, source)
+	}
+
+	return &apiv1beta3.Alert{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1beta3.GroupVersion.String(),
+			Kind:       apiv1beta3.ClusterAlertKind,
+		},
+		ObjectMeta: *clusterAlert.ObjectMeta.DeepCopy(),
+		Spec: apiv1beta3.AlertSpec{
+			ProviderRef:   clusterAlert.Spec.ProviderRef,
+			ProviderRefs:  clusterAlert.Spec.ProviderRefs,
+			EventSeverity: clusterAlert.Spec.EventSeverity,
+			EventSources:  eventSources,
+			InclusionList: clusterAlert.Spec.InclusionList,
+			EventMetadata: clusterAlert.Spec.EventMetadata,
+			ExclusionList: clusterAlert.Spec.ExclusionList,
+			Suspend:       clusterAlert.Spec.Suspend,
+		},
+	}
+}
+
+// isClusterAlert returns if the given Alert has been converted from a
+// ClusterAlert.
+func isClusterAlert(alert *apiv1beta3.Alert) bool {
+	return alert.Kind == apiv1beta3.ClusterAlertKind
+}
+
+// getProvider returns the Provider referenced by the given Alert. For Alerts
+// converted from ClusterAlerts, the referenced ClusterProvider is returned
+// converted to a Provider in the cluster resource namespace, so that its
+// Secret references are resolved in that namespace.
+func (s *EventServer) getProvider(ctx context.Context, alert *apiv1beta3.Alert) (*apiv1beta3.Provider, error) {
+	if !isClusterAlert(alert) {
+		var provider apiv1beta3.Provider
+		providerName := types.NamespacedName{Namespace: alert.Namespace, Name: alert.Spec.ProviderRef.Name}
+		if err := s.kubeClient.Get(ctx, providerName, &provider); err != nil {
+			return nil, err
+		}
+		return &provider, nil
+	}
+
+	var clusterProvider apiv1beta3.ClusterProvider
+	if err := s.kubeClient.Get(ctx, types.NamespacedName{Name: alert.Spec.ProviderRef.Name}, &clusterProvider); err != nil {
+		return nil, err
+	}
+
+	if s.clusterResourceNamespace == "" &&
+		(clusterProvider.Spec.SecretRef != nil || clusterProvider.Spec.CertSecretRef != nil) {
+		return nil, fmt.Errorf("cluster provider '%s' references a Secret, but no namespace is configured for cluster resources",
+			clusterProvider.Name)
+	}
+
+	provider := &apiv1beta3.Provider{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1beta3.GroupVersion.String(),
+			Kind:       apiv1beta3.ClusterProviderKind,
+		},
+		ObjectMeta: *clusterProvider.ObjectMeta.DeepCopy(),
+		Spec:       clusterProvider.Spec,
+	}
+	provider.Namespace = s.clusterResourceNamespace
+	return provider, nil
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1"
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/index"
+)
+
+func TestGetAllAlertsForEvent_ClusterAlerts(t *testing.T) {
+	tenantNamespace := &corev1.Namespace{}
+	tenantNamespace.Name = "tenant-ns"
+	tenantNamespace.Labels = map[string]string{"toolkit.fluxcd.io/tenant": "dev"}
+
+	otherNamespace := &corev1.Namespace{}
+	otherNamespace.Name = "other-ns"
+
+	namespacedAlert := &apiv1beta3.Alert{}
+	namespacedAlert.Name = "namespaced"
+	namespacedAlert.Namespace = tenantNamespace.Name
+	namespacedAlert.Spec = apiv1beta3.AlertSpec{
+		ProviderRef:   &meta.LocalObjectReference{Name: "provider"},
+		EventSeverity: eventv1.EventSeverityInfo,
+		EventSources: []apiv1.CrossNamespaceObjectReference{
+			{Kind: "Kustomization", Name: "*"},
+		},
+	}
+
+	newClusterAlert := func(name string, selector *metav1.LabelSelector, sources ...apiv1.CrossNamespaceObjectReference) *apiv1beta3.ClusterAlert {
+		a := &apiv1beta3.ClusterAlert{}
+		a.Name = name
+		a.Spec = apiv1beta3.ClusterAlertSpec{
+			ProviderRef:       &meta.LocalObjectReference{Name: "cluster-provider"},
+			NamespaceSelector: selector,
+			EventSeverity:     eventv1.EventSeverityInfo,
+			EventSources:      sources,
+		}
+		return a
+	}
+	allKustomizations := apiv1.CrossNamespaceObjectReference{Kind: "Kustomization", Name: "*"}
+
+	clusterAlerts := []*apiv1beta3.ClusterAlert{
+		newClusterAlert("all-namespaces", nil, allKustomizations),
+		newClusterAlert("tenants", &metav1.LabelSelector{
+			MatchLabels: map[string]string{"toolkit.fluxcd.io/tenant": "dev"},
+		}, allKustomizations),
+		newClusterAlert("explicit-namespace", nil, apiv1.CrossNamespaceObjectReference{
+			Kind: "Kustomization", Name: "*", Namespace: otherNamespace.Name,
+		}),
+		newClusterAlert("invalid-selector", &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "toolkit.fluxcd.io/tenant", Operator: "Invalid"},
+			},
+		}, allKustomizations),
+		newClusterAlert("helmreleases", nil, apiv1.CrossNamespaceObjectReference{Kind: "HelmRelease", Name: "*"}),
+	}
+
+	tests := []struct {
+		name       string
+		namespace  string
+		wantAlerts []string
+	}{
+		{
+			name:       "tenant namespace",
+			namespace:  tenantNamespace.Name,
+			wantAlerts: []string{"namespaced", "all-namespaces", "tenants"},
+		},
+		{
+			name:       "namespace not selected",
+			namespace:  otherNamespace.Name,
+			wantAlerts: []string{"all-namespaces", "explicit-namespace"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(apiv1beta3.AddToScheme(scheme)).ToNot(HaveOccurred())
+			g.Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
+			builder := fakeclient.NewClientBuilder().WithScheme(scheme).
+				WithIndex(&apiv1beta3.Alert{}, index.AlertEventSourceKey, index.AlertEventSources).
+				WithIndex(&apiv1beta3.ClusterAlert{}, index.ClusterAlertEventSourceKey, index.ClusterAlertEventSources).
+				WithObjects(tenantNamespace, otherNamespace, namespacedAlert)
+			for _, a := range clusterAlerts {
+				builder.WithObjects(a)
+			}
+			eventServer := EventServer{
+				kubeClient:    builder.Build(),
+				logger:        log.Log,
+				EventRecorder: record.NewFakeRecorder(32),
+			}
+
+			event := &eventv1.Event{
+				InvolvedObject: corev1.ObjectReference{
+					APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+					Kind:       "Kustomization",
+					Name:       "app",
+					Namespace:  tt.namespace,
+				},
+				Severity: eventv1.EventSeverityInfo,
+			}
+
+			alerts, err := eventServer.listAlertsForEvent(context.TODO(), event)
+			g.Expect(err).ToNot(HaveOccurred())
+			alerts = eventServer.filterAlertsForEvent(context.TODO(), alerts, event)
+
+			names := make([]string, 0, len(alerts))
+			for _, a := range alerts {
+				names = append(names, a.Name)
+				g.Expect(isClusterAlert(&a)).To(Equal(a.Name != namespacedAlert.Name))
+			}
+			g.Expect(names).To(ConsistOf(tt.wantAlerts))
+		})
+	}
+}
+
+func TestGetNotificationParams_ClusterProvider(t *testing.T) {
+	controllerNamespace := "flux-system"
+
+	testSecret := &corev1.Secret{}
+	testSecret.Name = "secret-foo"
+	testSecret.Namespace = controllerNamespace
+	testSecret.Data = map[string][]byte{"address": []byte("https://example.com/hook")}
+
+	testClusterProvider := &apiv1beta3.ClusterProvider{}
+	testClusterProvider.Name = "cluster-provider-foo"
+	testClusterProvider.Spec = apiv1beta3.ProviderSpec{
+		Type:      "generic",
+		SecretRef: &meta.LocalObjectReference{Name: testSecret.Name},
+	}
+
+	// A namespaced Provider with the same name must not be used.
+	testProvider := &apiv1beta3.Provider{}
+	testProvider.Name = testClusterProvider.Name
+	testProvider.Namespace = "tenant-ns"
+	testProvider.Spec = apiv1beta3.ProviderSpec{Type: "generic", Suspend: true}
+
+	clusterAlert := &apiv1beta3.ClusterAlert{}
+	clusterAlert.Name = "cluster-alert-foo"
+	clusterAlert.Spec = apiv1beta3.ClusterAlertSpec{
+		ProviderRef: &meta.LocalObjectReference{Name: testClusterProvider.Name},
+	}
+
+	event := &eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+			Kind:       "Kustomization",
+			Name:       "app",
+			Namespace:  "tenant-ns",
+		},
+	}
+
+	tests := []struct {
+		name                     string
+		clusterResourceNamespace string
+		wantErr                  bool
+	}{
+		{
+			name:                     "secret read from the controller namespace",
+			clusterResourceNamespace: controllerNamespace,
+		},
+		{
+			name:                     "secret in a different namespace",
+			clusterResourceNamespace: "tenant-ns",
+			wantErr:                  true,
+		},
+		{
+			name:    "no namespace configured for cluster resources",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			scheme := runtime.NewScheme()
+			g.Expect(apiv1beta3.AddToScheme(scheme)).ToNot(HaveOccurred())
+			g.Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
+			builder := fakeclient.NewClientBuilder().WithScheme(scheme).
+				WithObjects(testSecret, testClusterProvider, testProvider)
+			eventServer := EventServer{
+				kubeClient:               builder.Build(),
+				logger:                   log.Log,
+				noCrossNamespaceRefs:     true,
+				clusterResourceNamespace: tt.clusterResourceNamespace,
+				EventRecorder:            record.NewFakeRecorder(32),
+			}
+
+			alert := clusterAlertToAlert(clusterAlert, event.InvolvedObject.Namespace)
+			params, err := eventServer.getNotificationParams(context.TODO(), event, alert)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(params).ToNot(BeNil())
+			g.Expect(params.sender).ToNot(BeNil())
+			g.Expect(params.notification).ToNot(BeNil())
+		})
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/fluxcd/notification-controller/internal/notifier"
+)
+
+// maxRetryAfter is the maximum duration for which the notifications to a
+// Provider are paused after it responded with a Retry-After header.
+const maxRetryAfter = 5 * time.Minute
+
+// dispatchQueueFullRetryAfter is the Retry-After duration of the responses
+// to the events rejected because the dispatch queue is full.
+const dispatchQueueFullRetryAfter = 5 * time.Second
+
+// dispatcherPruneInterval is the minimum interval between the removals of
+// the queues and rate limiters of the idle Providers.
+const dispatcherPruneInterval = time.Minute
+
+// errDispatchQueueFull is returned when a notification can't be queued.
+var errDispatchQueueFull = errors.New("notification dispatch queue is full")
+
+// DispatcherOptions configures the concurrency and rate at which the
+// notifications are sent.
+type DispatcherOptions struct {
+	// Concurrency is the maximum number of notifications sent at once.
+	Concurrency int
+	// QueueSize is the maximum number of notifications waiting to be sent.
+	QueueSize int
+	// ProviderConcurrency is the maximum number of notifications sent at
+	// once to the same Provider.
+	ProviderConcurrency int
+	// ProviderRateLimit is the maximum number of notifications sent per
+	// second to the same Provider, zero meaning no limit.
+	ProviderRateLimit float64
+	// ProviderRateBurst is the number of notifications that can be sent at
+	// once to the same Provider above the rate limit.
+	ProviderRateBurst int
+}
+
+// dispatchJob sends a notification, returning the error of the Provider.
+type dispatchJob func() error
+
+// dispatcher sends the notifications from per-Provider queues, with bounded
+// global and per-Provider concurrency. Each Provider queue is served by up
+// to ProviderConcurrency goroutines, started on demand, so that a slow
+// Provider doesn't delay the notifications of the other Providers.
+type dispatcher struct {
+	opts  DispatcherOptions
+	slots chan struct{}
+
+	mu     sync.Mutex
+	queued int
+	lanes  map[providerReference]*dispatchLane
+	// limiters holds the rate limiters of the Providers, which outlive the
+	// queues so that the rate limit applies to the notifications sent one
+	// after another.
+	limiters  map[providerReference]*rate.Limiter
+	lastPrune time.Time
+}
+
+// dispatchLane is the queue of the notifications of a Provider.
+type dispatchLane struct {
+	jobs        []dispatchJob
+	runners     int
+	limiter     *rate.Limiter
+	pausedUntil time.Time
+}
+
+func newDispatcher(opts DispatcherOptions) *dispatcher {
+	opts.Concurrency = max(opts.Concurrency, 1)
+	opts.QueueSize = max(opts.QueueSize, 1)
+	opts.ProviderConcurrency = max(opts.ProviderConcurrency, 1)
+	opts.ProviderRateBurst = max(opts.ProviderRateBurst, 1)
+	return &dispatcher{
+		opts:     opts,
+		slots:    make(chan struct{}, opts.Concurrency),
+		lanes:    make(map[providerReference]*dispatchLane),
+		limiters: make(map[providerReference]*rate.Limiter),
+	}
+}
+
+// limiter returns the rate limiter of the given Provider, creating it if
+// needed. It must be called with the lock held.
+func (d *dispatcher) limiter(provider providerReference) *rate.Limiter {
+	if d.opts.ProviderRateLimit <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+
+	l, ok := d.limiters[provider]
+	if !ok {
+		l = rate.NewLimiter(rate.Limit(d.opts.ProviderRateLimit), d.opts.ProviderRateBurst)
+		d.limiters[provider] = l
+	}
+	return l
+}
+
+// prune removes, at most once per dispatcherPruneInterval, the queues of
+// the idle Providers whose pause has ended, as their runners exited before
+// the end of the pause, and the rate limiters of the idle Providers once
+// their burst is replenished, as they are then equivalent to new ones. It
+// must be called with the lock held.
+func (d *dispatcher) prune(now time.Time) {
+	if now.Sub(d.lastPrune) < dispatcherPruneInterval {
+		return
+	}
+	d.lastPrune = now
+	for p, lane := range d.lanes {
+		if lane.runners == 0 && len(lane.jobs) == 0 && now.After(lane.pausedUntil) {
+			delete(d.lanes, p)
+		}
+	}
+	for p, l := range d.limiters {
+		if _, busy := d.lanes[p]; !busy && l.TokensAt(now) >= float64(l.Burst()) {
+			delete(d.limiters, p)
+		}
+	}
+}
+
+// saturated returns if the queue is full. A nil dispatcher is never
+// saturated.
+func (d *dispatcher) saturated() bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queued >= d.opts.QueueSize
+}
+
+// enqueue queues the given job in the queue of the given Provider. It returns
+// errDispatchQueueFull when the queue is full.
+func (d *dispatcher) enqueue(provider providerReference, job dispatchJob) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.queued >= d.opts.QueueSize {
+		return errDispatchQueueFull
+	}
+
+	d.prune(time.Now())
+	lane, ok := d.lanes[provider]
+	if !ok {
+		lane = &dispatchLane{limiter: d.limiter(provider)}
+		d.lanes[provider] = lane
+	}
+	lane.jobs = append(lane.jobs, job)
+	d.queued++
+
+	if lane.runners < d.opts.ProviderConcurrency {
+		lane.runners++
+		go d.run(provider, lane)
+	}
+	return nil
+}
+
+// run sends the notifications of the given Provider queue until it's empty.
+func (d *dispatcher) run(provider providerReference, lane *dispatchLane) {
+	ctx := context.Background()
+	for {
+		d.mu.Lock()
+		if len(lane.jobs) == 0 {
+			lane.runners--
+			// Forget the idle Providers, unless paused.
+			if lane.runners == 0 && time.Now().After(lane.pausedUntil) {
+				delete(d.lanes, provider)
+			}
+			d.mu.Unlock()
+			return
+		}
+		job := lane.jobs[0]
+		lane.jobs[0] = nil
+		lane.jobs = lane.jobs[1:]
+		d.queued--
+		pausedUntil := lane.pausedUntil
+		d.mu.Unlock()
+
+		if wait := time.Until(pausedUntil); wait > 0 {
+			time.Sleep(wait)
+		}
+		_ = lane.limiter.Wait(ctx)
+
+		d.slots <- struct{}{}
+		err := job()
+		<-d.slots
+
+		var retryAfter *notifier.RetryAfterError
+		if errors.As(err, &retryAfter) && retryAfter.After > 0 {
+			until := time.Now().Add(min(retryAfter.After, maxRetryAfter))
+			d.mu.Lock()
+			if until.After(lane.pausedUntil) {
+				lane.pausedUntil = until
+			}
+			d.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+)
+
+func TestDispatcher_queueFull(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newDispatcher(DispatcherOptions{QueueSize: 2})
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+	job := func() error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	// The first job is taken off the queue by the runner of the Provider.
+	g.Expect(d.enqueue(providerReference{Name: "a"}, job)).To(Succeed())
+	g.Eventually(started).Should(Receive())
+
+	g.Expect(d.enqueue(providerReference{Name: "a"}, job)).To(Succeed())
+	g.Expect(d.saturated()).To(BeFalse())
+	g.Expect(d.enqueue(providerReference{Name: "b"}, job)).To(Succeed())
+	g.Expect(d.saturated()).To(BeTrue())
+	g.Expect(d.enqueue(providerReference{Name: "c"}, job)).To(MatchError(errDispatchQueueFull))
+
+	close(release)
+	g.Eventually(d.saturated).Should(BeFalse())
+
+	var nilDispatcher *dispatcher
+	g.Expect(nilDispatcher.saturated()).To(BeFalse())
+}
+
+func TestDispatcher_concurrency(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newDispatcher(DispatcherOptions{
+		Concurrency:         3,
+		QueueSize:           100,
+		ProviderConcurrency: 2,
+	})
+
+	var running, maxRunning, maxRunningA atomic.Int32
+	var runningA atomic.Int32
+	track := func(counter, maxCounter *atomic.Int32) {
+		n := counter.Add(1)
+		for {
+			m := maxCounter.Load()
+			if n <= m || maxCounter.CompareAndSwap(m, n) {
+				return
+			}
+		}
+	}
+	started := make(chan string, 100)
+	release := make(chan struct{})
+	done := make(chan struct{}, 100)
+	job := func(provider string) dispatchJob {
+		return func() error {
+			track(&running, &maxRunning)
+			if provider == "a" {
+				track(&runningA, &maxRunningA)
+				defer runningA.Add(-1)
+			}
+			started <- provider
+			<-release
+			running.Add(-1)
+			done <- struct{}{}
+			return nil
+		}
+	}
+	slotsInUse := func() int {
+		return len(d.slots)
+	}
+
+	// The jobs of a single Provider are bounded by the Provider concurrency.
+	for range 5 {
+		g.Expect(d.enqueue(providerReference{Name: "a"}, job("a"))).To(Succeed())
+	}
+	g.Eventually(started).Should(Receive(Equal("a")))
+	g.Eventually(started).Should(Receive(Equal("a")))
+	g.Expect(slotsInUse()).To(Equal(2))
+	d.mu.Lock()
+	g.Expect(d.lanes[providerReference{Name: "a"}].runners).To(Equal(2))
+	d.mu.Unlock()
+
+	// The jobs of all the Providers are bounded by the global concurrency,
+	// the blocked jobs holding their slot until released.
+	for range 5 {
+		for _, provider := range []string{"b", "c"} {
+			g.Expect(d.enqueue(providerReference{Name: provider}, job(provider))).To(Succeed())
+		}
+	}
+	g.Eventually(started).Should(Receive())
+	g.Expect(slotsInUse()).To(Equal(3))
+	g.Expect(running.Load()).To(Equal(int32(3)))
+
+	close(release)
+	for range 15 {
+		g.Eventually(done).Should(Receive())
+	}
+	g.Expect(maxRunning.Load()).To(Equal(int32(3)))
+	g.Expect(maxRunningA.Load()).To(Equal(int32(2)))
+
+	// The idle Providers are forgotten.
+	g.Eventually(func() int {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return len(d.lanes)
+	}).Should(BeZero())
+}
+
+func TestDispatcher_rateLimitSequential(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newDispatcher(DispatcherOptions{
+		QueueSize:         10,
+		ProviderRateLimit: 5,
+		ProviderRateBurst: 1,
+	})
+
+	sent := make(chan time.Time, 3)
+	send := func() error {
+		sent <- time.Now()
+		return nil
+	}
+	idle := func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		return len(d.lanes) == 0
+	}
+
+	// The notifications sent one after another, each after the queue of the
+	// previous one is forgotten, are still rate limited.
+	var times []time.Time
+	for range 3 {
+		g.Expect(d.enqueue(providerReference{Name: "a"}, send)).To(Succeed())
+		var at time.Time
+		g.Eventually(sent).Should(Receive(&at))
+		times = append(times, at)
+		g.Eventually(idle).Should(BeTrue())
+	}
+	for i := 1; i < len(times); i++ {
+		g.Expect(times[i].Sub(times[i-1])).To(BeNumerically(">=", 180*time.Millisecond))
+	}
+
+	// The other Providers have their own rate limit.
+	g.Expect(d.enqueue(providerReference{Name: "b"}, send)).To(Succeed())
+	g.Eventually(sent, 100*time.Millisecond).Should(Receive())
+}
+
+func TestDispatcher_rateLimiterExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newDispatcher(DispatcherOptions{
+		QueueSize:         10,
+		ProviderRateLimit: 1000,
+		ProviderRateBurst: 1,
+	})
+
+	d.mu.Lock()
+	l := d.limiter(providerReference{Name: "a"})
+	g.Expect(l.Allow()).To(BeTrue())
+	g.Expect(d.limiters).To(HaveLen(1))
+	d.mu.Unlock()
+
+	// Once replenished, the limiter of the idle Provider is removed.
+	time.Sleep(10 * time.Millisecond)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastPrune = time.Time{}
+	d.prune(time.Now())
+	d.limiter(providerReference{Name: "b"})
+	g.Expect(d.limiters).To(HaveLen(1))
+	g.Expect(d.limiters).To(HaveKey(providerReference{Name: "b"}))
+}
+
+func TestDispatcher_pausedLaneExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newDispatcher(DispatcherOptions{QueueSize: 10})
+
+	// The runner of the paused Provider exits before the end of the pause.
+	done := make(chan struct{})
+	g.Expect(d.enqueue(providerReference{Name: "a"}, func() error {
+		defer close(done)
+		return &notifier.RetryAfterError{After: 10 * time.Millisecond, Err: errors.New("too many requests")}
+	})).To(Succeed())
+	<-done
+	g.Eventually(func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		lane, ok := d.lanes[providerReference{Name: "a"}]
+		return ok && lane.runners == 0
+	}).Should(BeTrue())
+
+	// The queue of the idle Provider is removed once the pause has ended.
+	time.Sleep(20 * time.Millisecond)
+	d.mu.Lock()
+	d.lastPrune = time.Time{}
+	d.mu.Unlock()
+	g.Expect(d.enqueue(providerReference{Name: "b"}, func() error { return nil })).To(Succeed())
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	g.Expect(d.lanes).ToNot(HaveKey(providerReference{Name: "a"}))
+}
+
+func TestDispatcher_retryAfter(t *testing.T) {
+	g := NewWithT(t)
+
+	d := newDispatcher(DispatcherOptions{QueueSize: 10})
+
+	sent := make(chan time.Time, 2)
+	g.Expect(d.enqueue(providerReference{Name: "a"}, func() error {
+		sent <- time.Now()
+		return &notifier.RetryAfterError{After: 200 * time.Millisecond, Err: errors.New("too many requests")}
+	})).To(Succeed())
+	var first time.Time
+	g.Eventually(sent).Should(Receive(&first))
+
+	// Wait for the pause to be recorded before queueing the next job.
+	g.Eventually(func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		lane, ok := d.lanes[providerReference{Name: "a"}]
+		return ok && lane.pausedUntil.After(first)
+	}).Should(BeTrue())
+
+	g.Expect(d.enqueue(providerReference{Name: "a"}, func() error {
+		sent <- time.Now()
+		return nil
+	})).To(Succeed())
+	var second time.Time
+	g.Eventually(sent).Should(Receive(&second))
+	g.Expect(second.Sub(first)).To(BeNumerically(">=", 200*time.Millisecond))
+
+	// The other Providers are not paused.
+	g.Expect(d.enqueue(providerReference{Name: "b"}, func() error {
+		sent <- time.Now()
+		return nil
+	})).To(Succeed())
+	g.Eventually(sent, 100*time.Millisecond).Should(Receive())
+}
+
+func TestHandleEvent_dispatchQueueFull(t *testing.T) {
+	g := NewWithT(t)
+
+	s := &EventServer{dispatcher: newDispatcher(DispatcherOptions{QueueSize: 1})}
+	s.dispatcher.queued = 1
+
+	event := &eventv1.Event{}
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), eventContextKey{}, event))
+	rec := httptest.NewRecorder()
+	s.handleEvent()(rec, req)
+
+	g.Expect(rec.Code).To(Equal(http.StatusServiceUnavailable))
+	g.Expect(rec.Header().Get("Retry-After")).To(Equal("5"))
+}
+
+func TestDispatchNotification_queueFull(t *testing.T) {
+	g := NewWithT(t)
+
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "provider"
+	provider.Namespace = "foo-ns"
+	provider.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: "https://example.com"}
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert"
+	alert.Namespace = "foo-ns"
+	alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: provider.Name}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+	s := &EventServer{
+		kubeClient: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(provider).Build(),
+		logger:     log.Log,
+		dispatcher: newDispatcher(DispatcherOptions{QueueSize: 1}),
+	}
+	WithMetricsRegisterer(prometheus.NewRegistry())(s)
+	s.dispatcher.queued = 1
+
+	// The notifications that can't be queued are counted as failed.
+	err := s.dispatchNotification(context.TODO(), &eventv1.Event{}, alert)
+	g.Expect(err).To(MatchError(errDispatchQueueFull))
+	g.Expect(testutil.ToFloat64(s.metrics.notificationsTotal.WithLabelValues(
+		apiv1beta3.GenericProvider, "foo-ns", "provider", "foo-ns", "alert", notificationResultFailed))).To(Equal(float64(1)))
+}
@@ -18,30 +18,35 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"crypto/x509"
-	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
-	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/yaml"
 
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
 	pkgcache "github.com/fluxcd/pkg/cache"
-	"github.com/fluxcd/pkg/masktoken"
 
 	apiv1 "github.com/fluxcd/notification-controller/api/v1"
 	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/index"
 	"github.com/fluxcd/notification-controller/internal/notifier"
 )
 
@@ -58,19 +63,42 @@ func (s *EventServer) handleEvent() func(w http.ResponseWriter, r *http.Request)
 		event := r.Context().Value(eventContextKey{}).(*eventv1.Event)
 		eventLogger := log.FromContext(r.Context())
 
+		// Ask the sender to retry later when the notifications can't be
+		// queued.
+		if s.dispatcher.saturated() {
+			eventLogger.Info("discarding event, notification dispatch queue is full")
+			s.metrics.recordEventDiscarded(discardReasonQueueFull)
+			w.Header().Set("Retry-After", strconv.Itoa(int(dispatchQueueFullRetryAfter.Seconds())))
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
 		ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 		defer cancel()
 
 		// Remove any internal metadata before further processing the event.
 		excludeInternalMetadata(event)
 
-		alerts, err := s.getAllAlertsForEvent(ctx, event)
+		fctx, span := startSpan(ctx, "alerts.filter")
+		alerts, err := s.listAlertsForEvent(fctx, event)
 		if err != nil {
 			eventLogger.Error(err, "failed to get alerts for the event")
 		}
+		matched := s.filterAlertsForEvent(fctx, alerts, event)
+		span.SetAttributes(attribute.Int("alerts.listed", len(alerts)), attribute.Int("alerts.matched", len(matched)))
+		endSpan(span, err)
 
 		if len(alerts) == 0 {
 			eventLogger.Info("discarding event, no alerts found for the involved object")
+			s.metrics.recordEventDiscarded(discardReasonNoAlert)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		alerts = matched
+		if len(alerts) == 0 {
+			eventLogger.Info("discarding event, filtered out by the alerts of the involved object")
+			s.metrics.recordEventDiscarded(discardReasonFiltered)
 			w.WriteHeader(http.StatusAccepted)
 			return
 		}
@@ -82,7 +110,21 @@ func (s *EventServer) handleEvent() func(w http.ResponseWriter, r *http.Request)
 			alert := &alerts[i]
 			alertLogger := eventLogger.WithValues(alert.Kind, client.ObjectKeyFromObject(alert))
 			ctx := log.IntoContext(ctx, alertLogger)
+			// Keep track of the involved object state for the alert to
+			// detect its recovery from a failure dispatched for the alert.
+			resolution, transition := s.objectStates.observe(alert, event)
+			switch transition {
+			case objectResolved:
+				alertLogger.V(1).Info("involved object recovered", "failingSince", resolution.FailingSince)
+				ctx = notifier.WithResolution(ctx, resolution)
+			case objectUntracked:
+				ctx = notifier.WithUntrackedRecovery(ctx)
+			}
 			if err := s.dispatchNotification(ctx, event, alert); err != nil {
+				// Resolve the failure with the next recovery event.
+				if transition == objectResolved {
+					s.objectStates.restore(alert, event, resolution)
+				}
 				alertLogger.Error(err, "failed to dispatch notification")
 				s.Eventf(alert, corev1.EventTypeWarning, "NotificationDispatchFailed",
 					"failed to dispatch notification for %s: %s", involvedObjectString(event.InvolvedObject), err)
@@ -93,14 +135,25 @@ func (s *EventServer) handleEvent() func(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func (s *EventServer) getAllAlertsForEvent(ctx context.Context, event *eventv1.Event) ([]apiv1beta3.Alert, error) {
+// listAlertsForEvent returns the Alerts, and the ClusterAlerts converted to
+// Alerts, with event sources of the involved object kind, to be filtered
+// with filterAlertsForEvent.
+func (s *EventServer) listAlertsForEvent(ctx context.Context, event *eventv1.Event) ([]apiv1beta3.Alert, error) {
+	// List only the Alerts with event sources of the involved object
+	// namespace and kind.
 	var allAlerts apiv1beta3.AlertList
-	err := s.kubeClient.List(ctx, &allAlerts)
+	err := s.kubeClient.List(ctx, &allAlerts, client.MatchingFields{
+		index.AlertEventSourceKey: index.AlertEventSourceValue(event.InvolvedObject.Namespace, event.InvolvedObject.Kind),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed listing alerts: %w", err)
 	}
 
-	return s.filterAlertsForEvent(ctx, allAlerts.Items, event), nil
+	// Evaluate the ClusterAlerts alongside the namespaced Alerts. A failure
+	// to read the ClusterAlerts must not prevent the namespaced Alerts from
+	// being dispatched.
+	clusterAlerts, err := s.getClusterAlertsForEvent(ctx, event)
+	return append(allAlerts.Items, clusterAlerts...), err
 }
 
 // filterAlertsForEvent filters a given set of alerts against a given event,
@@ -160,15 +213,15 @@ func (s *EventServer) messageIsIncluded(ctx context.Context, msg string, alert *
 		return true
 	}
 
-	for _, exp := range alert.Spec.InclusionList {
-		if r, err := regexp.Compile(exp); err == nil {
-			if r.Match([]byte(msg)) {
+	for _, exp := range s.alertMatchers.get(alert).inclusionList {
+		if exp.err == nil {
+			if exp.regexp.MatchString(msg) {
 				return true
 			}
 		} else {
-			log.FromContext(ctx).Error(err, fmt.Sprintf("failed to compile inclusion regex: %s", exp))
+			log.FromContext(ctx).Error(exp.err, fmt.Sprintf("failed to compile inclusion regex: %s", exp.expr))
 			s.Eventf(alert, corev1.EventTypeWarning,
-				"InvalidConfig", "failed to compile inclusion regex: %s", exp)
+				"InvalidConfig", "failed to compile inclusion regex: %s", exp.expr)
 		}
 	}
 	return false
@@ -181,93 +234,386 @@ func (s *EventServer) messageIsExcluded(ctx context.Context, msg string, alert *
 		return false
 	}
 
-	for _, exp := range alert.Spec.ExclusionList {
-		if r, err := regexp.Compile(exp); err == nil {
-			if r.Match([]byte(msg)) {
+	for _, exp := range s.alertMatchers.get(alert).exclusionList {
+		if exp.err == nil {
+			if exp.regexp.MatchString(msg) {
 				return true
 			}
 		} else {
-			log.FromContext(ctx).Error(err, fmt.Sprintf("failed to compile exclusion regex: %s", exp))
+			log.FromContext(ctx).Error(exp.err, fmt.Sprintf("failed to compile exclusion regex: %s", exp.expr))
 			s.Eventf(alert, corev1.EventTypeWarning, "InvalidConfig",
-				"failed to compile exclusion regex: %s", exp)
+				"failed to compile exclusion regex: %s", exp.expr)
 		}
 	}
 	return false
 }
 
-// dispatchNotification constructs and sends notification from the given event
-// and alert data.
+// dispatchNotification constructs and sends notifications from the given
+// event and alert data to all the Providers referenced by the alert, in
+// parallel.
 func (s *EventServer) dispatchNotification(ctx context.Context, event *eventv1.Event, alert *apiv1beta3.Alert) error {
-	sender, notification, token, timeout, err := s.getNotificationParams(ctx, event, alert)
+	refs := alert.GetProviderRefs()
+	errs := make([]error, len(refs))
+
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.dispatchProviderNotification(ctx, event, alert, ref)
+		}()
+	}
+	wg.Wait()
+
+	return kerrors.NewAggregate(errs)
+}
+
+// dispatchProviderNotification constructs and sends notification from the
+// given event and alert data to the given Provider of the alert.
+func (s *EventServer) dispatchProviderNotification(ctx context.Context, event *eventv1.Event,
+	alert *apiv1beta3.Alert, ref apiv1beta3.AlertProviderReference) error {
+	providerAlert := alertForProvider(alert, ref)
+	provider := providerReferenceFor(providerAlert)
+	if provider.Kind == apiv1beta3.ClusterProviderKind {
+		// The Secrets of the ClusterProviders are read from the cluster
+		// resource namespace.
+		provider.Namespace = s.clusterResourceNamespace
+	}
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues(provider.Kind,
+		types.NamespacedName{Namespace: provider.Namespace, Name: provider.Name}))
+
+	params, err := s.getNotificationParams(ctx, event, providerAlert)
 	if err != nil {
+		s.notificationFailed(ctx, event, providerAlert, params, err)
 		return err
 	}
-	// Skip when either sender or notification couldn't be created.
-	if sender == nil || notification == nil {
+	// Skip when the Provider is suspended.
+	if params == nil {
 		return nil
 	}
 
-	go func(n notifier.Interface, e eventv1.Event) {
-		pctx, cancel := context.WithTimeout(context.Background(), timeout)
-		defer cancel()
-		if err := n.Post(pctx, e); err != nil {
-			maskedErrStr, maskErr := masktoken.MaskTokenFromString(err.Error(), token)
-			if maskErr != nil {
-				err = maskErr
-			} else {
-				err = errors.New(maskedErrStr)
-			}
-			log.FromContext(ctx).Error(err, "failed to send notification")
-			s.Eventf(alert, corev1.EventTypeWarning, "NotificationDispatchFailed",
-				"failed to send notification for %s: %s", involvedObjectString(event.InvolvedObject), err)
+	// Pass on the resolution of the involved object failure, with the
+	// metadata of the failed event combined in the same way as when the
+	// failure was dispatched, or the unknown state of the object.
+	pctx := detachedSpanContext(ctx)
+	if r, ok := notifier.ResolutionFromContext(ctx); ok {
+		if metadata, _ := combinedEventMetadata(&r.FailedEvent, providerAlert); len(metadata) > 0 {
+			r.FailedEvent.Metadata = metadata
+		}
+		pctx = notifier.WithResolution(pctx, r)
+	}
+	if notifier.IsUntrackedRecovery(ctx) {
+		pctx = notifier.WithUntrackedRecovery(pctx)
+	}
+
+	// The notification is sent after the event has been handled, hence the
+	// Provider fallbacks are read without the request deadline.
+	fctx := context.WithoutCancel(ctx)
+	failed := func(err error) {
+		if err == nil {
+			return
+		}
+		// Resolve the failure with the next recovery event.
+		if r, ok := notifier.ResolutionFromContext(ctx); ok {
+			s.objectStates.restore(alert, event, r)
+		}
+		log.FromContext(ctx).Error(err, "failed to send notification")
+		s.Eventf(alert, corev1.EventTypeWarning, "NotificationDispatchFailed",
+			"failed to send notification for %s: %s", involvedObjectString(event.InvolvedObject), err)
+	}
+	job := func() error {
+		err := s.sendProviderNotification(pctx, event, providerAlert, params)
+		if err != nil {
+			s.failoverNotification(fctx, pctx, event, providerAlert, err, failed)
 		}
-	}(sender, *notification)
+		return err
+	}
 
+	if err := s.enqueueNotification(providerReferenceFor(providerAlert), job); err != nil {
+		s.notificationFailed(ctx, event, providerAlert, params, err)
+		return err
+	}
 	return nil
 }
 
+// notificationFailed records in the metrics and the audit sink a notification
+// to the Provider of the given alert that failed with the given error before
+// being sent. The given parameters hold at least the Provider once it has
+// been read, otherwise they are nil.
+func (s *EventServer) notificationFailed(ctx context.Context, event *eventv1.Event, alert *apiv1beta3.Alert,
+	params *notificationParams, err error) {
+	s.recordAudit(ctx, event, alert, params, 0, err)
+
+	var provider *apiv1beta3.Provider
+	if params != nil {
+		provider = params.provider
+	}
+	if provider == nil {
+		ref := providerReferenceFor(alert)
+		provider = &apiv1beta3.Provider{ObjectMeta: metav1.ObjectMeta{Namespace: ref.Namespace, Name: ref.Name}}
+		if ref.Kind == apiv1beta3.ClusterProviderKind {
+			provider.Namespace = s.clusterResourceNamespace
+		}
+	}
+	s.metrics.recordNotificationFailed(alert, provider)
+}
+
+// enqueueNotification queues the given job in the dispatch queue of the
+// given Provider, or sends it right away without dispatcher.
+func (s *EventServer) enqueueNotification(provider providerReference, job dispatchJob) error {
+	if s.dispatcher == nil {
+		go job()
+		return nil
+	}
+	return s.dispatcher.enqueue(provider, job)
+}
+
+// providerReference identifies a Provider or ClusterProvider.
+type providerReference struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// providerReferenceFor returns the reference to the Provider of the given
+// alert.
+func providerReferenceFor(alert *apiv1beta3.Alert) providerReference {
+	if isClusterAlert(alert) {
+		return providerReference{Kind: apiv1beta3.ClusterProviderKind, Name: alert.Spec.ProviderRef.Name}
+	}
+	return providerReference{Kind: apiv1beta3.ProviderKind, Namespace: alert.Namespace, Name: alert.Spec.ProviderRef.Name}
+}
+
+// sendProviderNotification sends the given notification to the Provider of
+// the given alert, unless the circuit breaker of the Provider is open.
+func (s *EventServer) sendProviderNotification(ctx context.Context, event *eventv1.Event,
+	alert *apiv1beta3.Alert, params *notificationParams) error {
+	provider := providerReferenceFor(alert)
+	if err := s.circuitBreakers.allow(provider, params.provider); err != nil {
+		s.metrics.recordCircuitSkipped(provider)
+		s.recordAudit(ctx, event, alert, params, 0, err)
+		return err
+	}
+	ctx, span := startSpan(ctx, "provider.send",
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(providerAttributes(provider)...))
+	start := time.Now()
+	err := postNotification(ctx, params.sender, *params.notification, params.masker, params.timeout)
+	latency := time.Since(start)
+	endSpan(span, err)
+	s.circuitBreakers.record(provider, params.provider, err)
+	s.recordAudit(ctx, event, alert, params, latency, err)
+	return err
+}
+
+// postNotification sends the given notification with the given notifier
+// within the given timeout, masking the secret values of the Provider in the
+// returned error.
+func postNotification(ctx context.Context, n notifier.Interface, e eventv1.Event, masker secretMasker, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return masker.maskError(n.Post(ctx, e))
+}
+
+// failoverNotification sends the notification for the given event and alert
+// to the fallback Providers of the alert Provider, in order, after sending
+// it to the alert Provider failed with the given error. The notification is
+// queued for each fallback Provider, subject to its own concurrency and rate
+// limits, and the next fallback Provider is tried once it failed. The given
+// done function is called with nil as soon as a fallback Provider succeeds,
+// otherwise with an error describing the chain of Providers tried.
+func (s *EventServer) failoverNotification(ctx, pctx context.Context, event *eventv1.Event,
+	alert *apiv1beta3.Alert, postErr error, done func(error)) {
+	provider, err := s.getProvider(ctx, alert)
+	if err != nil || len(provider.Spec.FallbackProviderRefs) == 0 {
+		done(postErr)
+		return
+	}
+
+	f := &failover{
+		server:    s,
+		ctx:       ctx,
+		pctx:      pctx,
+		event:     event,
+		alert:     alert,
+		provider:  provider,
+		path:      []string{alert.Spec.ProviderRef.Name},
+		errs:      []error{fmt.Errorf("provider '%s': %w", alert.Spec.ProviderRef.Name, postErr)},
+		done:      done,
+		fallbacks: provider.Spec.FallbackProviderRefs,
+	}
+	f.next()
+}
+
+// failover is the state of the failover of a notification to the fallback
+// Providers. Its fallback Providers are tried one after the other, hence
+// it's never accessed concurrently.
+type failover struct {
+	server   *EventServer
+	ctx      context.Context
+	pctx     context.Context
+	event    *eventv1.Event
+	alert    *apiv1beta3.Alert
+	provider *apiv1beta3.Provider
+	path     []string
+	errs     []error
+	done     func(error)
+	// fallbacks holds the fallback Providers not tried yet.
+	fallbacks []meta.LocalObjectReference
+}
+
+// next queues the notification for the next fallback Provider, calling the
+// done function once there are none left.
+func (f *failover) next() {
+	s := f.server
+	logger := log.FromContext(f.ctx)
+	primary := f.path[0]
+
+	for len(f.fallbacks) > 0 {
+		ref := f.fallbacks[0]
+		f.fallbacks = f.fallbacks[1:]
+		if slices.Contains(f.path, ref.Name) {
+			continue
+		}
+		f.path = append(f.path, ref.Name)
+		logger.Error(f.errs[len(f.errs)-1], "failed to send notification, trying fallback provider",
+			"fallback", ref.Name)
+
+		fallbackAlert := f.alert.DeepCopy()
+		fallbackAlert.Spec.ProviderRef = &ref
+		params, err := s.getNotificationParams(f.ctx, f.event, fallbackAlert)
+		if err == nil && params == nil {
+			// Skip suspended fallback Providers.
+			continue
+		}
+		if err == nil {
+			err = s.enqueueNotification(providerReferenceFor(fallbackAlert), func() error {
+				err := s.sendProviderNotification(f.pctx, f.event, fallbackAlert, params)
+				if err == nil {
+					s.metrics.recordFailover(f.provider.Namespace, primary, ref.Name, failoverResultSuccess)
+					logger.Info("notification sent to fallback provider",
+						"fallback", ref.Name, "path", strings.Join(f.path, " -> "))
+					f.done(nil)
+					return nil
+				}
+				f.failed(ref.Name, err)
+				f.next()
+				return err
+			})
+		}
+		if err == nil {
+			return
+		}
+		s.notificationFailed(f.ctx, f.event, fallbackAlert, params, err)
+		f.failed(ref.Name, err)
+	}
+
+	f.done(fmt.Errorf("all providers failed (%s): %w", strings.Join(f.path, " -> "), kerrors.NewAggregate(f.errs)))
+}
+
+// failed records the failure of the given fallback Provider.
+func (f *failover) failed(fallback string, err error) {
+	f.server.metrics.recordFailover(f.provider.Namespace, f.path[0], fallback, failoverResultFailure)
+	f.errs = append(f.errs, fmt.Errorf("provider '%s': %w", fallback, err))
+}
+
+// alertForProvider returns a copy of the given alert with the given Provider
+// reference as the ProviderRef and the reference overrides applied.
+func alertForProvider(alert *apiv1beta3.Alert, ref apiv1beta3.AlertProviderReference) *apiv1beta3.Alert {
+	providerAlert := alert.DeepCopy()
+	providerAlert.Spec.ProviderRef = &meta.LocalObjectReference{Name: ref.Name}
+	providerAlert.Spec.ProviderRefs = nil
+
+	if len(ref.EventMetadata) > 0 && providerAlert.Spec.EventMetadata == nil {
+		providerAlert.Spec.EventMetadata = make(map[string]string, len(ref.EventMetadata))
+	}
+	for k, v := range ref.EventMetadata {
+		providerAlert.Spec.EventMetadata[k] = v
+	}
+
+	if ref.Summary != "" {
+		if providerAlert.Spec.EventMetadata == nil {
+			providerAlert.Spec.EventMetadata = make(map[string]string, 1)
+		}
+		providerAlert.Spec.EventMetadata["summary"] = ref.Summary
+		providerAlert.Spec.Summary = ""
+	}
+
+	return providerAlert
+}
+
+// notificationParams holds what is needed for sending a notification to a
+// Provider.
+type notificationParams struct {
+	// sender is the notifier of the Provider.
+	sender notifier.Interface
+	// notification is the event to send, mutated based on the alert
+	// configuration.
+	notification *eventv1.Event
+	// masker masks the secret values of the Provider in the errors.
+	masker secretMasker
+	// timeout is the timeout of the Provider.
+	timeout time.Duration
+	// provider is the Provider.
+	provider *apiv1beta3.Provider
+	// commitStatus is the commit status ID for the git Providers.
+	commitStatus string
+}
+
 // getNotificationParams constructs the notification parameters from the given
-// event and alert, and returns a notifier, event, token and timeout for sending
-// the notification. The returned event is a mutated form of the input event
-// based on the alert configuration.
-func (s *EventServer) getNotificationParams(ctx context.Context, event *eventv1.Event, alert *apiv1beta3.Alert) (notifier.Interface, *eventv1.Event, string, time.Duration, error) {
-	// Check if event comes from a different namespace.
-	if s.noCrossNamespaceRefs && event.InvolvedObject.Namespace != alert.Namespace {
+// event and alert. It returns nil parameters when the Provider is suspended.
+// When failing after reading the Provider, the returned parameters hold the
+// Provider only.
+func (s *EventServer) getNotificationParams(ctx context.Context, event *eventv1.Event, alert *apiv1beta3.Alert) (*notificationParams, error) {
+	// Check if event comes from a different namespace. ClusterAlerts are
+	// not subject to this restriction as they are not namespaced.
+	if s.noCrossNamespaceRefs && !isClusterAlert(alert) && event.InvolvedObject.Namespace != alert.Namespace {
 		accessDenied := fmt.Errorf(
 			"alert '%s/%s' can't process event from '%s', cross-namespace references have been blocked",
 			alert.Namespace, alert.Name, involvedObjectString(event.InvolvedObject))
-		return nil, nil, "", 0, fmt.Errorf("discarding event, access denied to cross-namespace sources: %w", accessDenied)
+		return nil, fmt.Errorf("discarding event, access denied to cross-namespace sources: %w", accessDenied)
 	}
 
-	var provider apiv1beta3.Provider
-	providerName := types.NamespacedName{Namespace: alert.Namespace, Name: alert.Spec.ProviderRef.Name}
-
-	err := s.kubeClient.Get(ctx, providerName, &provider)
+	provider, err := s.getProvider(ctx, alert)
 	if err != nil {
-		return nil, nil, "", 0, fmt.Errorf("failed to read provider: %w", err)
+		return nil, fmt.Errorf("failed to read provider: %w", err)
 	}
 
 	// Skip if the provider is suspended.
 	if provider.Spec.Suspend {
-		return nil, nil, "", 0, nil
+		return nil, nil
 	}
 
 	// Create a copy of the event and combine event metadata
 	notification := *event.DeepCopy()
+	_, span := startSpan(ctx, "event.metadata.combine")
 	s.combineEventMetadata(ctx, &notification, alert)
+	span.End()
 
 	// Create a commit status for the given provider and event, if applicable.
-	commitStatus, err := createCommitStatus(ctx, &provider, &notification, alert)
+	_, span = startSpan(ctx, "commitstatus.evaluate")
+	commitStatus, err := createCommitStatus(ctx, provider, &notification, alert)
+	endSpan(span, err)
 	if err != nil {
-		return nil, nil, "", 0, fmt.Errorf("failed to create commit status: %w", err)
+		return &notificationParams{provider: provider}, fmt.Errorf("failed to create commit status: %w", err)
 	}
 
-	sender, token, err := createNotifier(ctx, s.kubeClient, &provider, commitStatus, s.tokenCache)
+	nctx, span := startSpan(ctx, "notifier.create",
+		trace.WithAttributes(attribute.String("provider.type", provider.Spec.Type)))
+	sender, masker, err := s.getNotifier(nctx, provider, commitStatus)
+	endSpan(span, err)
 	if err != nil {
-		return nil, nil, "", 0, fmt.Errorf("failed to initialize notifier for provider '%s': %w", provider.Name, err)
-	}
-
-	return sender, &notification, token, provider.GetTimeout(), nil
+		return &notificationParams{provider: provider, masker: masker},
+			fmt.Errorf("failed to initialize notifier for provider '%s': %w", provider.Name, err)
+	}
+
+	return &notificationParams{
+		sender:       s.metrics.instrumentNotifier(alert, provider, sender),
+		notification: &notification,
+		masker:       masker,
+		timeout:      provider.GetTimeout(),
+		provider:     provider,
+		commitStatus: commitStatus,
+	}, nil
 }
 
 // createCommitStatus creates a commit status for the given provider and event.
@@ -292,10 +638,17 @@ func createCommitStatus(ctx context.Context, provider *apiv1beta3.Provider, even
 	return commitStatus, nil
 }
 
-// createNotifier returns a notifier.Interface for the given Provider.
-func createNotifier(ctx context.Context, kubeClient client.Client, provider *apiv1beta3.Provider, commitStatus string, tokenCache *pkgcache.TokenCache) (notifier.Interface, string, error) {
+// createNotifier returns a notifier.Interface for the given Provider,
+// created with the given notifier options in addition to the ones read from
+// the Provider.
+func createNotifier(ctx context.Context, kubeClient client.Client, provider *apiv1beta3.Provider, commitStatus string, tokenCache *pkgcache.TokenCache, opts ...notifier.Option) (_ notifier.Interface, masker secretMasker, err error) {
 	logger := log.FromContext(ctx)
 
+	// Mask the secret values in the errors, e.g. the invalid proxy URL.
+	defer func() {
+		err = masker.maskError(err)
+	}()
+
 	webhook := provider.Spec.Address
 	username := provider.Spec.Username
 	proxy := provider.Spec.Proxy
@@ -308,12 +661,13 @@ func createNotifier(ctx context.Context, kubeClient client.Client, provider *api
 
 		err := kubeClient.Get(ctx, secretName, &secret)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to read secret: %w", err)
+			return nil, masker, fmt.Errorf("failed to read secret: %w", err)
 		}
+		masker = newSecretMasker(&secret)
 
 		if val, ok := secret.Data["address"]; ok {
 			if len(val) > 2048 {
-				return nil, "", fmt.Errorf("invalid address in secret: address exceeds maximum length of %d bytes", 2048)
+				return nil, masker, fmt.Errorf("invalid address in secret: address exceeds maximum length of %d bytes", 2048)
 			}
 			webhook = strings.TrimSpace(string(val))
 		}
@@ -326,7 +680,7 @@ func createNotifier(ctx context.Context, kubeClient client.Client, provider *api
 			proxy = strings.TrimSpace(string(val))
 			_, err := url.Parse(proxy)
 			if err != nil {
-				return nil, "", fmt.Errorf("invalid proxy in secret '%s': %w", proxy, err)
+				return nil, masker, fmt.Errorf("invalid proxy in secret '%s': %w", proxy, err)
 			}
 		}
 
@@ -341,49 +695,61 @@ func createNotifier(ctx context.Context, kubeClient client.Client, provider *api
 		if h, ok := secret.Data["headers"]; ok {
 			err := yaml.Unmarshal(h, &headers)
 			if err != nil {
-				return nil, "", fmt.Errorf("failed to read headers from secret: %w", err)
+				return nil, masker, fmt.Errorf("failed to read headers from secret: %w", err)
 			}
 		}
 	}
 
 	var certPool *x509.CertPool
+	var clientCert *tls.Certificate
 	if provider.Spec.CertSecretRef != nil {
 		var secret corev1.Secret
 		secretName := types.NamespacedName{Namespace: provider.Namespace, Name: provider.Spec.CertSecretRef.Name}
 
 		err := kubeClient.Get(ctx, secretName, &secret)
 		if err != nil {
-			return nil, "", fmt.Errorf("failed to read cert secret: %w", err)
+			return nil, masker, fmt.Errorf("failed to read cert secret: %w", err)
 		}
 
 		switch secret.Type {
 		case corev1.SecretTypeOpaque, corev1.SecretTypeTLS, "":
 		default:
-			return nil, "", fmt.Errorf("cannot use Secret '%s' to get TLS certificate: invalid Secret type: '%s'", secret.Name, secret.Type)
+			return nil, masker, fmt.Errorf("cannot use Secret '%s' to get TLS certificate: invalid Secret type: '%s'", secret.Name, secret.Type)
+		}
+
+		certFile, keyFile := secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]
+		if len(certFile) > 0 && len(keyFile) > 0 {
+			cert, err := tls.X509KeyPair(certFile, keyFile)
+			if err != nil {
+				return nil, masker, fmt.Errorf("invalid client certificate in Secret '%s': %w", secret.Name, err)
+			}
+			clientCert = &cert
 		}
 
 		caFile, ok := secret.Data["ca.crt"]
 		if !ok {
 			// TODO: Drop support for "caFile" field in v1 Provider API.
 			caFile, ok = secret.Data["caFile"]
-			if !ok {
-				return nil, "", fmt.Errorf("no 'ca.crt' key found in Secret '%s'", secret.Name)
+			if ok {
+				logger.Info("warning: specifying CA cert via 'caFile' is deprecated, please use 'ca.crt' instead")
+			} else if clientCert == nil {
+				return nil, masker, fmt.Errorf("no 'ca.crt' key found in Secret '%s'", secret.Name)
 			}
-			logger.Info("warning: specifying CA cert via 'caFile' is deprecated, please use 'ca.crt' instead")
 		}
 
-		certPool = x509.NewCertPool()
-		ok = certPool.AppendCertsFromPEM(caFile)
-		if !ok {
-			return nil, "", fmt.Errorf("could not append to cert pool")
+		if ok {
+			certPool, err = notifier.NewCertPool(caFile)
+			if err != nil {
+				return nil, masker, err
+			}
 		}
 	}
 
 	if webhook == "" {
-		return nil, "", fmt.Errorf("provider has no address")
+		return nil, masker, fmt.Errorf("provider has no address")
 	}
 
-	options := []notifier.Option{}
+	options := slices.Clone(opts)
 
 	if commitStatus != "" {
 		options = append(options, notifier.WithCommitStatus(commitStatus))
@@ -413,6 +779,10 @@ func createNotifier(ctx context.Context, kubeClient client.Client, provider *api
 		options = append(options, notifier.WithCertPool(certPool))
 	}
 
+	if clientCert != nil {
+		options = append(options, notifier.WithClientCertificate(clientCert))
+	}
+
 	if password != "" {
 		options = append(options, notifier.WithPassword(password))
 	}
@@ -436,9 +806,9 @@ func createNotifier(ctx context.Context, kubeClient client.Client, provider *api
 	factory := notifier.NewFactory(webhook, options...)
 	sender, err := factory.Notifier(provider.Spec.Type)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to initialize notifier: %w", err)
+		return nil, masker, fmt.Errorf("failed to initialize notifier: %w", err)
 	}
-	return sender, token, nil
+	return sender, masker, nil
 }
 
 // eventMatchesAlertSource returns if a given event matches with the given alert
@@ -453,9 +823,10 @@ func (s *EventServer) eventMatchesAlertSource(ctx context.Context, event *eventv
 	}
 
 	// No match if the alert severity doesn't match the event severity and
-	// the alert severity isn't info.
+	// the alert severity isn't info, unless the event resolves a failure
+	// of the involved object dispatched for the alert.
 	severity := alert.Spec.EventSeverity
-	if event.Severity != severity && severity != eventv1.EventSeverityInfo {
+	if event.Severity != severity && severity != eventv1.EventSeverityInfo && !s.objectStates.resolves(alert, event) {
 		return false
 	}
 
@@ -471,15 +842,8 @@ func (s *EventServer) eventMatchesAlertSource(ctx context.Context, event *eventv
 	}
 
 	// Perform label selector matching.
-	var obj metav1.PartialObjectMetadata
-	obj.SetGroupVersionKind(event.InvolvedObject.GroupVersionKind())
-	obj.SetName(event.InvolvedObject.Name)
-	obj.SetNamespace(event.InvolvedObject.Namespace)
-
-	if err := s.kubeClient.Get(ctx, types.NamespacedName{
-		Namespace: event.InvolvedObject.Namespace,
-		Name:      event.InvolvedObject.Name,
-	}, &obj); err != nil {
+	obj, err := s.getInvolvedObjectMetadata(ctx, event)
+	if err != nil {
 		logger.Error(err, "error getting the involved object")
 		s.Eventf(alert, corev1.EventTypeWarning, "SourceFetchFailed",
 			"error getting source object %s", involvedObjectString(event.InvolvedObject))
@@ -499,6 +863,25 @@ func (s *EventServer) eventMatchesAlertSource(ctx context.Context, event *eventv
 	return sel.Matches(labels.Set(obj.GetLabels()))
 }
 
+// getInvolvedObjectMetadata returns the metadata of the object involved in
+// the given event, from the metadata informers when configured.
+func (s *EventServer) getInvolvedObjectMetadata(ctx context.Context, event *eventv1.Event) (*metav1.PartialObjectMetadata, error) {
+	key := types.NamespacedName{
+		Namespace: event.InvolvedObject.Namespace,
+		Name:      event.InvolvedObject.Name,
+	}
+	if s.objectMetadata != nil {
+		return s.objectMetadata.get(ctx, event.InvolvedObject.GroupVersionKind(), key)
+	}
+
+	var obj metav1.PartialObjectMetadata
+	obj.SetGroupVersionKind(event.InvolvedObject.GroupVersionKind())
+	if err := s.kubeClient.Get(ctx, key, &obj); err != nil {
+		return nil, err
+	}
+	return &obj, nil
+}
+
 // combineEventMetadata combines all the sources of metadata for the event
 // according to the precedence order defined in RFC 0008. From lowest to
 // highest precedence, the sources are:
@@ -515,6 +898,43 @@ func (s *EventServer) eventMatchesAlertSource(ctx context.Context, event *eventv
 // info-level log is emitted to warn users about all the conflicts,
 // but only if at least one conflict is found.
 func (s *EventServer) combineEventMetadata(ctx context.Context, event *eventv1.Event, alert *apiv1beta3.Alert) {
+	l := log.FromContext(ctx)
+
+	if alert.Spec.Summary != "" {
+		l.Info("warning: specifying an alert summary with '.spec.summary' is deprecated, use '.spec.eventMetadata.summary' instead")
+	}
+
+	metadata, metadataSources := combinedEventMetadata(event, alert)
+
+	// Detect key conflicts and emit warnings if any.
+	type keyConflict struct {
+		Key     string   `json:"key"`
+		Sources []string `json:"sources"`
+	}
+	var conflictingKeys []*keyConflict
+	conflictEventAnnotations := make(map[string]string)
+	for key, sources := range metadataSources {
+		if len(sources) > 1 {
+			conflictingKeys = append(conflictingKeys, &keyConflict{key, sources})
+			conflictEventAnnotations[key] = strings.Join(sources, ", ")
+		}
+	}
+	if len(conflictingKeys) > 0 {
+		const msg = "metadata key conflicts detected (please refer to the Alert API docs and Flux RFC 0008 for more information)"
+		slices.SortFunc(conflictingKeys, func(a, b *keyConflict) int { return strings.Compare(a.Key, b.Key) })
+		l.Info("warning: "+msg, "conflictingKeys", conflictingKeys)
+		s.AnnotatedEventf(alert, conflictEventAnnotations, corev1.EventTypeWarning, "MetadataAppendFailed", "%s", msg)
+	}
+
+	if len(metadata) > 0 {
+		event.Metadata = metadata
+	}
+}
+
+// combinedEventMetadata returns the combined metadata of the given event and
+// alert as described in combineEventMetadata, along with the sources of each
+// metadata key.
+func combinedEventMetadata(event *eventv1.Event, alert *apiv1beta3.Alert) (map[string]string, map[string][]string) {
 	const (
 		sourceEventGroup         = "involved object annotations"
 		sourceAlertEventMetadata = "Alert object .spec.eventMetadata"
@@ -524,7 +944,6 @@ func (s *EventServer) combineEventMetadata(ctx context.Context, event *eventv1.E
 		summaryKey = "summary"
 	)
 
-	l := log.FromContext(ctx)
 	metadata := make(map[string]string)
 	metadataSources := make(map[string][]string)
 
@@ -548,7 +967,6 @@ func (s *EventServer) combineEventMetadata(ctx context.Context, event *eventv1.E
 	if alert.Spec.Summary != "" {
 		metadata[summaryKey] = alert.Spec.Summary
 		metadataSources[summaryKey] = append(metadataSources[summaryKey], sourceAlertSummary)
-		l.Info("warning: specifying an alert summary with '.spec.summary' is deprecated, use '.spec.eventMetadata.summary' instead")
 	}
 
 	// 4) Event metadata keys prefixed with the involved object's API Group stripped of the prefix.
@@ -561,29 +979,7 @@ func (s *EventServer) combineEventMetadata(ctx context.Context, event *eventv1.E
 		}
 	}
 
-	// Detect key conflicts and emit warnings if any.
-	type keyConflict struct {
-		Key     string   `json:"key"`
-		Sources []string `json:"sources"`
-	}
-	var conflictingKeys []*keyConflict
-	conflictEventAnnotations := make(map[string]string)
-	for key, sources := range metadataSources {
-		if len(sources) > 1 {
-			conflictingKeys = append(conflictingKeys, &keyConflict{key, sources})
-			conflictEventAnnotations[key] = strings.Join(sources, ", ")
-		}
-	}
-	if len(conflictingKeys) > 0 {
-		const msg = "metadata key conflicts detected (please refer to the Alert API docs and Flux RFC 0008 for more information)"
-		slices.SortFunc(conflictingKeys, func(a, b *keyConflict) int { return strings.Compare(a.Key, b.Key) })
-		l.Info("warning: "+msg, "conflictingKeys", conflictingKeys)
-		s.AnnotatedEventf(alert, conflictEventAnnotations, corev1.EventTypeWarning, "MetadataAppendFailed", "%s", msg)
-	}
-
-	if len(metadata) > 0 {
-		event.Metadata = metadata
-	}
+	return metadata, metadataSources
 }
 
 // excludeInternalMetadata removes any internal metadata from the given event.
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -38,6 +40,8 @@ import (
 
 	apiv1 "github.com/fluxcd/notification-controller/api/v1"
 	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+	"github.com/fluxcd/notification-controller/internal/policy"
 )
 
 func TestFilterAlertsForEvent(t *testing.T) {
@@ -261,7 +265,7 @@ func TestFilterAlertsForEvent(t *testing.T) {
 			alerts := []apiv1beta3.Alert{}
 			for i, alertSpec := range tt.alertSpecs {
 				// Add the default provider ref for this test.
-				alertSpec.ProviderRef = meta.LocalObjectReference{Name: testProvider.Name}
+				alertSpec.ProviderRef = &meta.LocalObjectReference{Name: testProvider.Name}
 				// Create new Alert with the spec.
 				alert := apiv1beta3.Alert{}
 				alert.Name = "test-alert-" + strconv.Itoa(i)
@@ -308,7 +312,7 @@ func TestDispatchNotification(t *testing.T) {
 	testAlert.Name = "alert-foo"
 	testAlert.Namespace = testNamespace
 	testAlert.Spec = apiv1beta3.AlertSpec{
-		ProviderRef: meta.LocalObjectReference{Name: testProvider.Name},
+		ProviderRef: &meta.LocalObjectReference{Name: testProvider.Name},
 	}
 
 	// Event involved object.
@@ -324,11 +328,33 @@ func TestDispatchNotification(t *testing.T) {
 		name              string
 		providerNamespace string
 		providerSuspended bool
+		noProviderRef     bool
+		providerRefs      []apiv1beta3.AlertProviderReference
 		wantErr           bool
 	}{
 		{
 			name: "dispatch notification successfully",
 		},
+		{
+			name: "dispatch notification to multiple providers successfully",
+			providerRefs: []apiv1beta3.AlertProviderReference{
+				{Name: testProvider.Name, Summary: "foo"},
+			},
+		},
+		{
+			name:          "dispatch notification to provider list only successfully",
+			noProviderRef: true,
+			providerRefs: []apiv1beta3.AlertProviderReference{
+				{Name: testProvider.Name},
+			},
+		},
+		{
+			name: "one of multiple providers not found",
+			providerRefs: []apiv1beta3.AlertProviderReference{
+				{Name: "provider-bar"},
+			},
+			wantErr: true,
+		},
 		{
 			name:              "provider in different namespace",
 			providerNamespace: "bar-ns",
@@ -352,6 +378,10 @@ func TestDispatchNotification(t *testing.T) {
 				provider.Namespace = tt.providerNamespace
 			}
 			provider.Spec.Suspend = tt.providerSuspended
+			alert.Spec.ProviderRefs = tt.providerRefs
+			if tt.noProviderRef {
+				alert.Spec.ProviderRef = nil
+			}
 
 			// Create fake objects and event server.
 			scheme := runtime.NewScheme()
@@ -371,6 +401,111 @@ func TestDispatchNotification(t *testing.T) {
 	}
 }
 
+func TestDispatchNotification_restoresFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	rcvServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rcvServer.Close()
+
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "provider-foo"
+	provider.Namespace = "foo-ns"
+	provider.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: rcvServer.URL}
+	alert := &apiv1beta3.Alert{}
+	alert.Kind = apiv1beta3.AlertKind
+	alert.Name = "alert-foo"
+	alert.Namespace = "foo-ns"
+	alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: provider.Name}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+	eventServer := EventServer{
+		kubeClient:    fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(provider).Build(),
+		logger:        log.Log,
+		EventRecorder: record.NewFakeRecorder(32),
+		objectStates:  newObjectStateTracker(),
+	}
+
+	involvedObj := corev1.ObjectReference{Kind: "Kustomization", Name: "foo", Namespace: "foo-ns"}
+	failure := &eventv1.Event{InvolvedObject: involvedObj, Severity: eventv1.EventSeverityError, Reason: "Failed"}
+	recovery := &eventv1.Event{InvolvedObject: involvedObj, Severity: eventv1.EventSeverityInfo, Reason: "Succeeded"}
+	eventServer.objectStates.observe(alert, failure)
+	resolution, transition := eventServer.objectStates.observe(alert, recovery)
+	g.Expect(transition).To(Equal(objectResolved))
+
+	// The failure is resolved by the next recovery event, as the
+	// notification of the resolution failed.
+	ctx := notifier.WithResolution(context.TODO(), resolution)
+	g.Expect(eventServer.dispatchNotification(ctx, recovery, alert)).To(Succeed())
+	g.Eventually(func() bool {
+		return eventServer.objectStates.resolves(alert, recovery)
+	}).Should(BeTrue())
+}
+
+func TestAlertForProvider(t *testing.T) {
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert-foo"
+	alert.Spec = apiv1beta3.AlertSpec{
+		ProviderRef: &meta.LocalObjectReference{Name: "provider-foo"},
+		ProviderRefs: []apiv1beta3.AlertProviderReference{
+			{Name: "provider-bar"},
+		},
+		Summary: "alert summary",
+		EventMetadata: map[string]string{
+			"env":     "staging",
+			"cluster": "foo",
+		},
+	}
+
+	tests := []struct {
+		name              string
+		ref               apiv1beta3.AlertProviderReference
+		wantSummary       string
+		wantEventMetadata map[string]string
+	}{
+		{
+			name:        "no overrides",
+			ref:         apiv1beta3.AlertProviderReference{Name: "provider-bar"},
+			wantSummary: "alert summary",
+			wantEventMetadata: map[string]string{
+				"env":     "staging",
+				"cluster": "foo",
+			},
+		},
+		{
+			name: "event metadata and summary overrides",
+			ref: apiv1beta3.AlertProviderReference{
+				Name:          "provider-bar",
+				Summary:       "provider summary",
+				EventMetadata: map[string]string{"env": "production"},
+			},
+			wantEventMetadata: map[string]string{
+				"env":     "production",
+				"cluster": "foo",
+				"summary": "provider summary",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			result := alertForProvider(alert, tt.ref)
+			g.Expect(result.Spec.ProviderRef.Name).To(Equal(tt.ref.Name))
+			g.Expect(result.Spec.ProviderRefs).To(BeEmpty())
+			g.Expect(result.Spec.Summary).To(Equal(tt.wantSummary))
+			g.Expect(result.Spec.EventMetadata).To(Equal(tt.wantEventMetadata))
+
+			// The original alert must be left untouched.
+			g.Expect(alert.Spec.EventMetadata).To(HaveLen(2))
+			g.Expect(alert.Spec.ProviderRefs).To(HaveLen(1))
+		})
+	}
+}
+
 func TestGetNotificationParams(t *testing.T) {
 	testNamespace := "foo-ns"
 
@@ -392,7 +527,7 @@ func TestGetNotificationParams(t *testing.T) {
 	testAlert.Name = "alert-foo"
 	testAlert.Namespace = testNamespace
 	testAlert.Spec = apiv1beta3.AlertSpec{
-		ProviderRef: meta.LocalObjectReference{Name: testProvider.Name},
+		ProviderRef: &meta.LocalObjectReference{Name: testProvider.Name},
 	}
 
 	// Event involved object.
@@ -509,8 +644,12 @@ func TestGetNotificationParams(t *testing.T) {
 				EventRecorder:        record.NewFakeRecorder(32),
 			}
 
-			_, n, _, _, err := eventServer.getNotificationParams(context.TODO(), event, alert)
+			params, err := eventServer.getNotificationParams(context.TODO(), event, alert)
 			g.Expect(err != nil).To(Equal(tt.wantErr))
+			var n *eventv1.Event
+			if params != nil {
+				n = params.notification
+			}
 			if tt.alertSummary != "" {
 				g.Expect(n.Metadata["summary"]).To(Equal(tt.alertSummary))
 			}
@@ -1012,6 +1151,7 @@ func TestEventMatchesAlert(t *testing.T) {
 		source        apiv1.CrossNamespaceObjectReference
 		severity      string
 		resourcesFile string
+		failingAlert  string
 		wantResult    bool
 	}{
 		{
@@ -1050,6 +1190,36 @@ func TestEventMatchesAlert(t *testing.T) {
 			severity:   "error",
 			wantResult: false,
 		},
+		{
+			name: "event and alert severity mismatch, event resolves failure",
+			event: &eventv1.Event{
+				InvolvedObject: involvedObj,
+				Severity:       "info",
+			},
+			source: apiv1.CrossNamespaceObjectReference{
+				Kind:      "Kustomization",
+				Name:      "*",
+				Namespace: testNamespace,
+			},
+			severity:     "error",
+			failingAlert: "test-alert",
+			wantResult:   true,
+		},
+		{
+			name: "event and alert severity mismatch, event resolves failure of another alert",
+			event: &eventv1.Event{
+				InvolvedObject: involvedObj,
+				Severity:       "info",
+			},
+			source: apiv1.CrossNamespaceObjectReference{
+				Kind:      "Kustomization",
+				Name:      "*",
+				Namespace: testNamespace,
+			},
+			severity:     "error",
+			failingAlert: "other-alert",
+			wantResult:   false,
+		},
 		{
 			name: "event and alert severity mismatch, alert severity info",
 			event: &eventv1.Event{
@@ -1163,6 +1333,7 @@ func TestEventMatchesAlert(t *testing.T) {
 				kubeClient:    builder.Build(),
 				logger:        log.Log,
 				EventRecorder: record.NewFakeRecorder(32),
+				objectStates:  newObjectStateTracker(),
 			}
 			alert := &apiv1beta3.Alert{
 				ObjectMeta: metav1.ObjectMeta{
@@ -1174,6 +1345,16 @@ func TestEventMatchesAlert(t *testing.T) {
 				},
 			}
 
+			// Dispatch a failure of the involved object for the alert.
+			if tt.failingAlert != "" {
+				failingAlert := alert.DeepCopy()
+				failingAlert.Name = tt.failingAlert
+				eventServer.objectStates.observe(failingAlert, &eventv1.Event{
+					InvolvedObject: involvedObj,
+					Severity:       eventv1.EventSeverityError,
+				})
+			}
+
 			result := eventServer.eventMatchesAlertSource(context.TODO(), tt.event, alert, tt.source)
 			g.Expect(result).To(Equal(tt.wantResult))
 		})
@@ -1355,3 +1536,31 @@ func Test_excludeInternalMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestEventServer_getNotifier_providerTypePolicy(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	g.Expect(os.WriteFile(path, []byte("rules:\n- namespaces: [team-a]\n  allowedTypes: [slack]\n"), 0o600)).To(Succeed())
+	typePolicy, err := policy.LoadProviderTypePolicy(path)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tenant := &corev1.Namespace{}
+	tenant.Name = "team-a"
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "dispatch"
+	provider.Namespace = "team-a"
+	provider.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GitHubDispatchProvider, Address: "https://github.com/org/repo"}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	s := &EventServer{
+		kubeClient:         fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(tenant, provider).Build(),
+		logger:             log.Log,
+		providerTypePolicy: typePolicy,
+	}
+
+	_, _, err = s.getNotifier(context.Background(), provider, "")
+	g.Expect(err).To(MatchError(policy.ErrProviderTypeNotAllowed))
+}
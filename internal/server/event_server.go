@@ -29,21 +29,34 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sethvargo/go-limiter"
 	"github.com/sethvargo/go-limiter/httplimit"
 	"github.com/slok/go-http-metrics/middleware"
 	"github.com/slok/go-http-metrics/middleware/std"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/types"
 	kuberecorder "k8s.io/client-go/tools/record"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
 	pkgcache "github.com/fluxcd/pkg/cache"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+	"github.com/fluxcd/notification-controller/internal/policy"
 )
 
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=notification.toolkit.fluxcd.io,resources=alerts,verbs=get;list
 // +kubebuilder:rbac:groups=notification.toolkit.fluxcd.io,resources=providers,verbs=get
+// +kubebuilder:rbac:groups=notification.toolkit.fluxcd.io,resources=clusteralerts,verbs=get;list;watch
+// +kubebuilder:rbac:groups=notification.toolkit.fluxcd.io,resources=clusterproviders,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 type eventContextKey struct{}
 
@@ -55,12 +68,95 @@ type EventServer struct {
 	noCrossNamespaceRefs  bool
 	exportHTTPPathMetrics bool
 	tokenCache            *pkgcache.TokenCache
+	objectStates          *objectStateTracker
+	alertMatchers         *alertMatcherCache
+	// clusterResourceNamespace is the namespace from which the Secrets
+	// referenced by ClusterProviders are read.
+	clusterResourceNamespace string
+	metrics                  *eventServerMetrics
+	notifierCache            *NotifierCache
+	objectMetadata           *objectMetadataReader
+	dispatcher               *dispatcher
+	circuitBreakers          *circuitBreakers
+	auditSink                AuditSink
+	providerTypePolicy       *policy.ProviderTypePolicy
+	egressPolicy             *notifier.EgressPolicy
+	splunkHECBatcher         *notifier.SplunkHECBatcher
 	kuberecorder.EventRecorder
 }
 
+// EventServerOption represents a functional option for configuring an
+// EventServer.
+type EventServerOption func(*EventServer)
+
+// WithClusterResourceNamespace sets the namespace from which the Secrets
+// referenced by ClusterProviders are read.
+func WithClusterResourceNamespace(namespace string) EventServerOption {
+	return func(s *EventServer) {
+		s.clusterResourceNamespace = namespace
+	}
+}
+
+// WithMetricsRegisterer registers the event server metrics with the given
+// registerer.
+func WithMetricsRegisterer(reg prometheus.Registerer) EventServerOption {
+	return func(s *EventServer) {
+		s.metrics = newEventServerMetrics(reg)
+	}
+}
+
+// WithNotifierCache sets the cache used for reusing the notifiers across
+// events.
+func WithNotifierCache(cache *NotifierCache) EventServerOption {
+	return func(s *EventServer) {
+		s.notifierCache = cache
+	}
+}
+
+// WithObjectMetadataCache sets the cache in which metadata-only informers
+// are started for reading the labels of the objects involved in events,
+// and the reader used for the objects of the kinds not in the cache.
+func WithObjectMetadataCache(cache ctrlcache.Cache, apiReader client.Reader) EventServerOption {
+	return func(s *EventServer) {
+		s.objectMetadata = newObjectMetadataReader(cache, apiReader, s.logger)
+	}
+}
+
+// WithDispatcherOptions bounds the concurrency and rate at which the
+// notifications are sent. Without it, every notification is sent as soon
+// as its event is handled.
+func WithDispatcherOptions(opts DispatcherOptions) EventServerOption {
+	return func(s *EventServer) {
+		s.dispatcher = newDispatcher(opts)
+	}
+}
+
+// WithCircuitBreakerOptions enables the circuit breakers of the Providers,
+// which skip the notifications to a Provider after consecutive failures.
+func WithCircuitBreakerOptions(opts CircuitBreakerOptions) EventServerOption {
+	return func(s *EventServer) {
+		s.circuitBreakers = newCircuitBreakers(opts, s.circuitStateChanged)
+	}
+}
+
+// WithProviderTypePolicy restricts the Provider types that can be used in
+// the namespaces.
+func WithProviderTypePolicy(typePolicy *policy.ProviderTypePolicy) EventServerOption {
+	return func(s *EventServer) {
+		s.providerTypePolicy = typePolicy
+	}
+}
+
+// WithEgressPolicy restricts the destinations the notifiers can connect to.
+func WithEgressPolicy(egressPolicy *notifier.EgressPolicy) EventServerOption {
+	return func(s *EventServer) {
+		s.egressPolicy = egressPolicy
+	}
+}
+
 // NewEventServer returns an HTTP server that handles events
-func NewEventServer(port string, logger logr.Logger, kubeClient client.Client, eventRecorder kuberecorder.EventRecorder, noCrossNamespaceRefs bool, exportHTTPPathMetrics bool, tokenCache *pkgcache.TokenCache) *EventServer {
-	return &EventServer{
+func NewEventServer(port string, logger logr.Logger, kubeClient client.Client, eventRecorder kuberecorder.EventRecorder, noCrossNamespaceRefs bool, exportHTTPPathMetrics bool, tokenCache *pkgcache.TokenCache, opts ...EventServerOption) *EventServer {
+	s := &EventServer{
 		port:                  port,
 		logger:                logger.WithName("event-server"),
 		kubeClient:            kubeClient,
@@ -68,7 +164,31 @@ func NewEventServer(port string, logger logr.Logger, kubeClient client.Client, e
 		noCrossNamespaceRefs:  noCrossNamespaceRefs,
 		exportHTTPPathMetrics: exportHTTPPathMetrics,
 		tokenCache:            tokenCache,
+		objectStates:          newObjectStateTracker(),
+		alertMatchers:         newAlertMatcherCache(),
+	}
+	s.splunkHECBatcher = notifier.NewSplunkHECBatcher(s.splunkHECBatchFailed)
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
+}
+
+// notifierOptions returns the options of the notifiers shared by all the
+// Providers.
+func (s *EventServer) notifierOptions() []notifier.Option {
+	return []notifier.Option{
+		notifier.WithEgressPolicy(s.egressPolicy),
+		notifier.WithSplunkHECBatcher(s.splunkHECBatcher),
+	}
+}
+
+// splunkHECBatchFailed reports the notifications of the given Provider which
+// were acknowledged once queued in a Splunk HEC batch, but failed to be sent.
+func (s *EventServer) splunkHECBatchFailed(namespace, name string, events int, err error) {
+	s.logger.Error(err, "failed to send the batched notifications",
+		"provider", types.NamespacedName{Namespace: namespace, Name: name}, "notifications", events)
+	s.metrics.recordBatchFailed(apiv1beta3.SplunkHECProvider, namespace, name, events)
 }
 
 // ListenAndServe starts the HTTP server on the specified port
@@ -78,10 +198,15 @@ func (s *EventServer) ListenAndServe(stopCh <-chan struct{}, mdlw middleware.Mid
 		s.logger.Error(err, "Event server crashed")
 		os.Exit(1)
 	}
+	if s.objectMetadata != nil {
+		if err := s.objectMetadata.watchAlertSources(context.Background()); err != nil {
+			s.logger.Error(err, "unable to watch Alerts for starting metadata informers")
+		}
+	}
 	var handler http.Handler = http.HandlerFunc(s.handleEvent())
 	for _, middleware := range []func(http.Handler) http.Handler{
 		limitMiddleware.Handle,
-		logRateLimitMiddleware,
+		s.logRateLimitMiddleware,
 		s.eventMiddleware,
 	} {
 		handler = middleware(handler)
@@ -147,10 +272,17 @@ func (s *EventServer) eventMiddleware(h http.Handler) http.Handler {
 		}
 
 		cleanupMetadata(event)
+		s.metrics.recordEventReceived(event)
 
 		eventLogger := s.logger.WithValues("eventInvolvedObject", event.InvolvedObject)
 
-		enhancedCtx := context.WithValue(r.Context(), eventContextKey{}, event)
+		// Continue the trace of the event sender, if any.
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := startSpan(ctx, "event.receive",
+			trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(eventAttributes(event)...))
+		defer span.End()
+
+		enhancedCtx := context.WithValue(ctx, eventContextKey{}, event)
 		enhancedCtx = log.IntoContext(enhancedCtx, eventLogger)
 		enhancedReq := r.WithContext(enhancedCtx)
 
@@ -201,7 +333,7 @@ func (r *statusRecorder) WriteHeader(status int) {
 	r.ResponseWriter.WriteHeader(status)
 }
 
-func logRateLimitMiddleware(h http.Handler) http.Handler {
+func (s *EventServer) logRateLimitMiddleware(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		recorder := &statusRecorder{
 			ResponseWriter: w,
@@ -212,6 +344,7 @@ func logRateLimitMiddleware(h http.Handler) http.Handler {
 		if recorder.Status == http.StatusTooManyRequests {
 			log.FromContext(r.Context()).V(1).
 				Info("Discarding event, rate limiting duplicate events")
+			s.metrics.recordEventDiscarded(discardReasonRateLimited)
 		}
 	})
 }
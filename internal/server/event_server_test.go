@@ -49,6 +49,7 @@ import (
 
 	apiv1 "github.com/fluxcd/notification-controller/api/v1"
 	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/index"
 )
 
 func TestEventServer(t *testing.T) {
@@ -76,7 +77,7 @@ func TestEventServer(t *testing.T) {
 	testAlert.Name = "alert-foo"
 	testAlert.Namespace = testNamespace
 	testAlert.Spec = apiv1beta3.AlertSpec{
-		ProviderRef:   meta.LocalObjectReference{Name: provider.Name},
+		ProviderRef:   &meta.LocalObjectReference{Name: provider.Name},
 		EventSeverity: "info",
 		EventSources: []apiv1.CrossNamespaceObjectReference{
 			{
@@ -114,7 +115,9 @@ func TestEventServer(t *testing.T) {
 	g.Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
 
 	// Create a fake kube client with the above objects.
-	builder := fakeclient.NewClientBuilder().WithScheme(scheme)
+	builder := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&apiv1beta3.Alert{}, index.AlertEventSourceKey, index.AlertEventSources).
+		WithIndex(&apiv1beta3.ClusterAlert{}, index.ClusterAlertEventSourceKey, index.ClusterAlertEventSources)
 	builder.WithObjects(provider, repo1, repo2)
 	kclient := builder.Build()
 
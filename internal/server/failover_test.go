@@ -0,0 +1,190 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestFailoverNotification(t *testing.T) {
+	testNamespace := "foo-ns"
+
+	var rejected, accepted atomic.Int32
+	rejectServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rejected.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejectServer.Close()
+	acceptServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer acceptServer.Close()
+
+	newProvider := func(name, address string, fallbacks ...string) *apiv1beta3.Provider {
+		p := &apiv1beta3.Provider{}
+		p.Name = name
+		p.Namespace = testNamespace
+		p.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: address}
+		for _, f := range fallbacks {
+			p.Spec.FallbackProviderRefs = append(p.Spec.FallbackProviderRefs, meta.LocalObjectReference{Name: f})
+		}
+		return p
+	}
+
+	event := &eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+			Kind:       "Kustomization",
+			Name:       "foo",
+			Namespace:  testNamespace,
+		},
+		Severity: eventv1.EventSeverityError,
+		Message:  "failed",
+	}
+
+	tests := []struct {
+		name         string
+		primary      *apiv1beta3.Provider
+		wantErr      string
+		wantRejected int32
+		wantAccepted int32
+		dispatcher   bool
+		wantSuccess  float64
+		wantFailure  float64
+		wantLanes    []string
+	}{
+		{
+			name:    "no fallbacks",
+			primary: newProvider("primary", rejectServer.URL),
+			wantErr: "primary failed",
+		},
+		{
+			name:         "first fallback fails, second succeeds",
+			primary:      newProvider("primary", rejectServer.URL, "primary", "reject", "missing", "accept", "reject"),
+			wantRejected: 1,
+			wantAccepted: 1,
+			wantSuccess:  1,
+			wantFailure:  2,
+		},
+		{
+			name:         "fallbacks queued in their own dispatch queues",
+			primary:      newProvider("primary", rejectServer.URL, "reject", "accept"),
+			dispatcher:   true,
+			wantRejected: 1,
+			wantAccepted: 1,
+			wantSuccess:  1,
+			wantFailure:  1,
+			wantLanes:    []string{"reject", "accept"},
+		},
+		{
+			name:         "all fallbacks fail",
+			primary:      newProvider("primary", rejectServer.URL, "reject", "suspended"),
+			wantErr:      "all providers failed (primary -> reject -> suspended)",
+			wantRejected: 1,
+			wantFailure:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			rejected.Store(0)
+			accepted.Store(0)
+
+			suspended := newProvider("suspended", acceptServer.URL)
+			suspended.Spec.Suspend = true
+
+			scheme := runtime.NewScheme()
+			g.Expect(apiv1beta3.AddToScheme(scheme)).ToNot(HaveOccurred())
+			g.Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
+			builder := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+				tt.primary,
+				newProvider("reject", rejectServer.URL),
+				newProvider("accept", acceptServer.URL),
+				suspended,
+			)
+			reg := prometheus.NewRegistry()
+			eventServer := EventServer{
+				kubeClient:    builder.Build(),
+				logger:        log.Log,
+				EventRecorder: record.NewFakeRecorder(32),
+				metrics:       newEventServerMetrics(reg),
+			}
+
+			alert := &apiv1beta3.Alert{}
+			alert.Name = "alert"
+			alert.Namespace = testNamespace
+			alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: tt.primary.Name}
+
+			if tt.dispatcher {
+				eventServer.dispatcher = newDispatcher(DispatcherOptions{QueueSize: 10, ProviderRateLimit: 1})
+			}
+
+			errs := make(chan error, 1)
+			eventServer.failoverNotification(context.TODO(), context.TODO(), event, alert,
+				errors.New("primary failed"), func(err error) { errs <- err })
+			var err error
+			g.Eventually(errs).Should(Receive(&err))
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(rejected.Load()).To(Equal(tt.wantRejected))
+			g.Expect(accepted.Load()).To(Equal(tt.wantAccepted))
+
+			var success, failure float64
+			for _, fallback := range []string{"reject", "accept", "missing", "suspended"} {
+				success += testutil.ToFloat64(eventServer.metrics.failoverTotal.WithLabelValues(
+					testNamespace, tt.primary.Name, fallback, failoverResultSuccess))
+				failure += testutil.ToFloat64(eventServer.metrics.failoverTotal.WithLabelValues(
+					testNamespace, tt.primary.Name, fallback, failoverResultFailure))
+			}
+			g.Expect(success).To(Equal(tt.wantSuccess))
+			g.Expect(failure).To(Equal(tt.wantFailure))
+
+			// The rate limiters of the Providers are kept by the dispatcher.
+			for _, name := range tt.wantLanes {
+				eventServer.dispatcher.mu.Lock()
+				g.Expect(eventServer.dispatcher.limiters).To(HaveKey(providerReference{
+					Kind: apiv1beta3.ProviderKind, Namespace: testNamespace, Name: name,
+				}))
+				eventServer.dispatcher.mu.Unlock()
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+)
+
+const (
+	failoverResultSuccess = "success"
+	failoverResultFailure = "failure"
+)
+
+// Reasons for which events are discarded.
+const (
+	discardReasonNoAlert     = "no_alert"
+	discardReasonFiltered    = "filtered"
+	discardReasonRateLimited = "rate_limited"
+	discardReasonQueueFull   = "queue_full"
+)
+
+// Results of the notifications sent to providers.
+const (
+	notificationResultSent   = "sent"
+	notificationResultFailed = "failed"
+)
+
+// eventServerMetrics holds the Prometheus collectors of the event server.
+// A nil *eventServerMetrics records nothing.
+type eventServerMetrics struct {
+	eventsReceivedTotal     *prometheus.CounterVec
+	eventsDiscardedTotal    *prometheus.CounterVec
+	notificationsTotal      *prometheus.CounterVec
+	providerRequestDuration *prometheus.HistogramVec
+	failoverTotal           *prometheus.CounterVec
+	circuitState            *prometheus.GaugeVec
+	circuitSkippedTotal     *prometheus.CounterVec
+	auditDroppedTotal       prometheus.Counter
+	batchFailedTotal        *prometheus.CounterVec
+}
+
+// newEventServerMetrics creates the event server collectors and registers
+// them with the given registerer.
+func newEventServerMetrics(reg prometheus.Registerer) *eventServerMetrics {
+	m := &eventServerMetrics{
+		eventsReceivedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotk_notification_events_received_total",
+			Help: "Total number of events received, by involved object kind, severity and reason.",
+		}, []string{"kind", "severity", "reason"}),
+		eventsDiscardedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotk_notification_events_discarded_total",
+			Help: "Total number of events discarded without notifications, by reason.",
+		}, []string{"reason"}),
+		notificationsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotk_notification_notifications_total",
+			Help: "Total number of notifications sent to providers, by provider type, namespace, name, alert namespace, alert name and result.",
+		}, []string{"type", "namespace", "name", "alert_namespace", "alert_name", "result"}),
+		providerRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "gotk_notification_provider_request_duration_seconds",
+			Help:    "Duration of the requests sending notifications to providers, retries included, by provider type, namespace and name.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"type", "namespace", "name"}),
+		failoverTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotk_notification_provider_failover_total",
+			Help: "Total number of notifications sent to a fallback provider, by primary provider, fallback provider and result.",
+		}, []string{"namespace", "provider", "fallback", "result"}),
+		circuitState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "gotk_notification_provider_circuit_breaker_state",
+			Help: "State of the circuit breaker of a provider: 0 for closed, 1 for half-open and 2 for open.",
+		}, []string{"kind", "namespace", "name"}),
+		circuitSkippedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotk_notification_provider_circuit_breaker_skipped_total",
+			Help: "Total number of notifications skipped while the circuit breaker of a provider is open.",
+		}, []string{"kind", "namespace", "name"}),
+		auditDroppedTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "gotk_notification_audit_records_dropped_total",
+			Help: "Total number of audit records dropped because the queue of the audit provider is full.",
+		}),
+		batchFailedTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "gotk_notification_batched_notifications_failed_total",
+			Help: "Total number of notifications acknowledged once batched that failed to be sent, by provider type, namespace and name.",
+		}, []string{"type", "namespace", "name"}),
+	}
+	reg.MustRegister(m.eventsReceivedTotal, m.eventsDiscardedTotal, m.notificationsTotal,
+		m.providerRequestDuration, m.failoverTotal, m.circuitState, m.circuitSkippedTotal,
+		m.auditDroppedTotal, m.batchFailedTotal)
+	return m
+}
+
+// recordEventReceived records the given event as received.
+func (m *eventServerMetrics) recordEventReceived(event *eventv1.Event) {
+	if m == nil {
+		return
+	}
+	m.eventsReceivedTotal.WithLabelValues(event.InvolvedObject.Kind, event.Severity, event.Reason).Inc()
+}
+
+// recordEventDiscarded records an event discarded for the given reason.
+func (m *eventServerMetrics) recordEventDiscarded(reason string) {
+	if m == nil {
+		return
+	}
+	m.eventsDiscardedTotal.WithLabelValues(reason).Inc()
+}
+
+// recordNotification records the result and duration of a notification
+// sent to the given provider for the given alert.
+func (m *eventServerMetrics) recordNotification(alert *apiv1beta3.Alert, provider *apiv1beta3.Provider,
+	duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	result := notificationResultSent
+	if err != nil {
+		result = notificationResultFailed
+	}
+	m.notificationsTotal.WithLabelValues(provider.Spec.Type, provider.Namespace, provider.Name,
+		alert.Namespace, alert.Name, result).Inc()
+	m.providerRequestDuration.WithLabelValues(provider.Spec.Type, provider.Namespace, provider.Name).
+		Observe(duration.Seconds())
+}
+
+// recordNotificationFailed records a notification to the given provider for
+// the given alert that failed before being sent, e.g. as the provider
+// couldn't be read or the notification couldn't be queued.
+func (m *eventServerMetrics) recordNotificationFailed(alert *apiv1beta3.Alert, provider *apiv1beta3.Provider) {
+	if m == nil {
+		return
+	}
+	m.notificationsTotal.WithLabelValues(provider.Spec.Type, provider.Namespace, provider.Name,
+		alert.Namespace, alert.Name, notificationResultFailed).Inc()
+}
+
+// recordFailover records the result of sending a notification to a fallback
+// provider of the given primary provider.
+func (m *eventServerMetrics) recordFailover(namespace, provider, fallback, result string) {
+	if m == nil {
+		return
+	}
+	m.failoverTotal.WithLabelValues(namespace, provider, fallback, result).Inc()
+}
+
+// recordCircuitState records the state of the circuit breaker of the given
+// provider.
+func (m *eventServerMetrics) recordCircuitState(provider providerReference, state circuitState) {
+	if m == nil {
+		return
+	}
+	m.circuitState.WithLabelValues(provider.Kind, provider.Namespace, provider.Name).Set(float64(state))
+}
+
+// recordCircuitSkipped records a notification skipped while the circuit
+// breaker of the given provider is open.
+func (m *eventServerMetrics) recordCircuitSkipped(provider providerReference) {
+	if m == nil {
+		return
+	}
+	m.circuitSkippedTotal.WithLabelValues(provider.Kind, provider.Namespace, provider.Name).Inc()
+}
+
+// recordAuditDropped records an audit record dropped because the queue of
+// the audit provider is full.
+func (m *eventServerMetrics) recordAuditDropped() {
+	if m == nil {
+		return
+	}
+	m.auditDroppedTotal.Inc()
+}
+
+// recordBatchFailed records the given number of notifications to the given
+// provider which were acknowledged once batched, but failed to be sent.
+func (m *eventServerMetrics) recordBatchFailed(providerType, namespace, name string, notifications int) {
+	if m == nil {
+		return
+	}
+	m.batchFailedTotal.WithLabelValues(providerType, namespace, name).Add(float64(notifications))
+}
+
+// instrumentedNotifier records the result and duration of the notifications
+// sent with the wrapped notifier.
+type instrumentedNotifier struct {
+	notifier.Interface
+	alert    *apiv1beta3.Alert
+	provider *apiv1beta3.Provider
+	metrics  *eventServerMetrics
+}
+
+func (n *instrumentedNotifier) Post(ctx context.Context, event eventv1.Event) error {
+	start := time.Now()
+	err := n.Interface.Post(ctx, event)
+	n.metrics.recordNotification(n.alert, n.provider, time.Since(start), err)
+	return err
+}
+
+// instrumentNotifier wraps the given notifier of the given provider for
+// recording the metrics of the notifications of the given alert.
+func (m *eventServerMetrics) instrumentNotifier(alert *apiv1beta3.Alert, provider *apiv1beta3.Provider,
+	n notifier.Interface) notifier.Interface {
+	if m == nil || n == nil {
+		return n
+	}
+	return &instrumentedNotifier{Interface: n, alert: alert, provider: provider, metrics: m}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1"
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/index"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+	"github.com/fluxcd/notification-controller/internal/policy"
+)
+
+func TestEventServerMetrics_events(t *testing.T) {
+	testNamespace := "foo-ns"
+
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert"
+	alert.Namespace = testNamespace
+	alert.Spec = apiv1beta3.AlertSpec{
+		ProviderRef:   &meta.LocalObjectReference{Name: "missing"},
+		EventSeverity: eventv1.EventSeverityInfo,
+		EventSources: []apiv1.CrossNamespaceObjectReference{
+			{Kind: "Kustomization", Name: "*"},
+		},
+		ExclusionList: []string{"excluded"},
+	}
+
+	scheme := runtime.NewScheme()
+	NewWithT(t).Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+	kubeClient := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(alert).
+		WithIndex(&apiv1beta3.Alert{}, index.AlertEventSourceKey, index.AlertEventSources).
+		WithIndex(&apiv1beta3.ClusterAlert{}, index.ClusterAlertEventSourceKey, index.ClusterAlertEventSources).
+		Build()
+
+	tests := []struct {
+		name        string
+		kind        string
+		message     string
+		wantReason  string
+		wantDiscard float64
+	}{
+		{
+			name:        "no alert",
+			kind:        "GitRepository",
+			message:     "stored artifact",
+			wantReason:  discardReasonNoAlert,
+			wantDiscard: 1,
+		},
+		{
+			name:        "filtered",
+			kind:        "Kustomization",
+			message:     "excluded",
+			wantReason:  discardReasonFiltered,
+			wantDiscard: 1,
+		},
+		{
+			name:       "dispatched",
+			kind:       "Kustomization",
+			message:    "applied",
+			wantReason: discardReasonFiltered,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reg := prometheus.NewRegistry()
+			eventServer := &EventServer{
+				kubeClient:    kubeClient,
+				logger:        log.Log,
+				EventRecorder: record.NewFakeRecorder(32),
+				objectStates:  newObjectStateTracker(),
+				metrics:       newEventServerMetrics(reg),
+			}
+
+			event := eventv1.Event{
+				InvolvedObject: corev1.ObjectReference{
+					APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+					Kind:       tt.kind,
+					Name:       "foo",
+					Namespace:  testNamespace,
+				},
+				Severity: eventv1.EventSeverityInfo,
+				Reason:   "ReconciliationSucceeded",
+				Message:  tt.message,
+			}
+			body, err := json.Marshal(event)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			eventServer.eventMiddleware(http.HandlerFunc(eventServer.handleEvent())).ServeHTTP(rec, req)
+			g.Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+			g.Expect(testutil.ToFloat64(eventServer.metrics.eventsReceivedTotal.WithLabelValues(
+				tt.kind, eventv1.EventSeverityInfo, "ReconciliationSucceeded"))).To(Equal(float64(1)))
+			g.Expect(testutil.ToFloat64(eventServer.metrics.eventsDiscardedTotal.WithLabelValues(
+				tt.wantReason))).To(Equal(tt.wantDiscard))
+		})
+	}
+}
+
+func TestEventServerMetrics_rateLimited(t *testing.T) {
+	g := NewWithT(t)
+
+	eventServer := &EventServer{metrics: newEventServerMetrics(prometheus.NewRegistry())}
+	handler := eventServer.logRateLimitMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	g.Expect(testutil.ToFloat64(eventServer.metrics.eventsDiscardedTotal.WithLabelValues(
+		discardReasonRateLimited))).To(Equal(float64(1)))
+}
+
+func TestEventServerMetrics_splunkHECBatchFailed(t *testing.T) {
+	g := NewWithT(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	s := NewEventServer("", log.Log, nil, record.NewFakeRecorder(10), false, false, nil,
+		WithMetricsRegisterer(prometheus.NewRegistry()))
+	opts := append(s.notifierOptions(),
+		notifier.WithToken("token"),
+		notifier.WithProviderNamespace("foo-ns"),
+		notifier.WithProviderName("splunk"),
+		notifier.WithSecretData(map[string][]byte{"batchSize": []byte("2"), "batchInterval": []byte("10ms")}))
+	n, err := notifier.NewFactory(ts.URL, opts...).Notifier(apiv1beta3.SplunkHECProvider)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The notification is acknowledged once batched, and the failure of its
+	// batch is recorded afterwards.
+	g.Expect(n.Post(context.Background(), eventv1.Event{})).To(Succeed())
+	g.Eventually(func() float64 {
+		return testutil.ToFloat64(s.metrics.batchFailedTotal.WithLabelValues(
+			apiv1beta3.SplunkHECProvider, "foo-ns", "splunk"))
+	}, 5*time.Second, 10*time.Millisecond).Should(Equal(float64(1)))
+}
+
+func TestEventServerMetrics_instrumentNotifier(t *testing.T) {
+	g := NewWithT(t)
+
+	m := newEventServerMetrics(prometheus.NewRegistry())
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "slack"
+	provider.Namespace = "foo-ns"
+	provider.Spec.Type = apiv1beta3.SlackProvider
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert"
+	alert.Namespace = "foo-ns"
+
+	n := m.instrumentNotifier(alert, provider, &failingNotifier{})
+	g.Expect(n.Post(context.Background(), eventv1.Event{})).To(Succeed())
+	n = m.instrumentNotifier(alert, provider, &failingNotifier{err: errors.New("failed")})
+	g.Expect(n.Post(context.Background(), eventv1.Event{})).ToNot(Succeed())
+
+	for _, result := range []string{notificationResultSent, notificationResultFailed} {
+		g.Expect(testutil.ToFloat64(m.notificationsTotal.WithLabelValues(
+			apiv1beta3.SlackProvider, "foo-ns", "slack", "foo-ns", "alert", result))).To(Equal(float64(1)))
+	}
+	g.Expect(testutil.CollectAndCount(m.providerRequestDuration)).To(Equal(1))
+
+	var nilMetrics *eventServerMetrics
+	notifier := &failingNotifier{}
+	g.Expect(nilMetrics.instrumentNotifier(alert, provider, notifier)).To(BeIdenticalTo(notifier))
+}
+
+func TestEventServerMetrics_notificationFailed(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(policyPath, []byte("rules:\n- namespaces: [foo-ns]\n  allowedTypes: [slack]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	typePolicy, err := policy.LoadProviderTypePolicy(policyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		provider      *apiv1beta3.Provider
+		typePolicy    *policy.ProviderTypePolicy
+		wantErr       string
+		wantTypeLabel string
+	}{
+		{
+			name:    "missing provider",
+			wantErr: "failed to read provider",
+		},
+		{
+			name: "notifier initialization failure",
+			provider: &apiv1beta3.Provider{
+				Spec: apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider},
+			},
+			wantErr:       "provider has no address",
+			wantTypeLabel: apiv1beta3.GenericProvider,
+		},
+		{
+			name: "provider type not allowed",
+			provider: &apiv1beta3.Provider{
+				Spec: apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: "https://example.com"},
+			},
+			typePolicy:    typePolicy,
+			wantErr:       "provider type not allowed",
+			wantTypeLabel: apiv1beta3.GenericProvider,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			namespace := &corev1.Namespace{}
+			namespace.Name = "foo-ns"
+			objects := []client.Object{namespace}
+			if tt.provider != nil {
+				tt.provider.Name = "provider"
+				tt.provider.Namespace = "foo-ns"
+				objects = append(objects, tt.provider)
+			}
+			alert := &apiv1beta3.Alert{}
+			alert.Name = "alert"
+			alert.Namespace = "foo-ns"
+			alert.Spec.ProviderRef = &meta.LocalObjectReference{Name: "provider"}
+
+			scheme := runtime.NewScheme()
+			g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+			g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			s := &EventServer{
+				kubeClient:         fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+				logger:             log.Log,
+				providerTypePolicy: tt.typePolicy,
+			}
+			WithMetricsRegisterer(prometheus.NewRegistry())(s)
+
+			// The notifications failing before being sent are counted as
+			// failed, with the provider type when the provider is read.
+			err := s.dispatchNotification(context.TODO(), &eventv1.Event{}, alert)
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			g.Expect(testutil.ToFloat64(s.metrics.notificationsTotal.WithLabelValues(
+				tt.wantTypeLabel, "foo-ns", "provider", "foo-ns", "alert", notificationResultFailed))).To(Equal(float64(1)))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	pkgcache "github.com/fluxcd/pkg/cache"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+)
+
+// notifierSecretsRecheckInterval is the interval at which the Secrets
+// referenced by a Provider are checked for changes when its notifier is
+// served from the cache.
+const notifierSecretsRecheckInterval = 30 * time.Second
+
+// NotifierCache holds the notifiers created for Providers, so that they are
+// reused across events instead of being created for every notification.
+type NotifierCache = pkgcache.LRU[cachedNotifier]
+
+// NewNotifierCache returns a NotifierCache holding at most the given number
+// of notifiers.
+func NewNotifierCache(capacity int, opts ...pkgcache.Options) (*NotifierCache, error) {
+	return pkgcache.NewLRU[cachedNotifier](capacity, opts...)
+}
+
+// cachedNotifier is a notifier created for a Provider, along with the
+// versions of the Provider and its Secrets it was created from.
+type cachedNotifier struct {
+	notifier       notifier.Interface
+	masker         secretMasker
+	generation     int64
+	secretVersions []string
+	checkedAt      time.Time
+}
+
+// getNotifier returns the notifier for the given Provider, from the cache
+// when the Provider and its Secrets didn't change since it was created.
+func (s *EventServer) getNotifier(ctx context.Context, provider *apiv1beta3.Provider, commitStatus string) (notifier.Interface, secretMasker, error) {
+	// The policy is checked before reusing a notifier, as the labels of the
+	// namespace may have changed.
+	if err := s.providerTypePolicy.Validate(ctx, s.kubeClient, provider); err != nil {
+		return nil, secretMasker{}, err
+	}
+
+	if s.notifierCache == nil || !isCacheableProvider(provider) {
+		return createNotifier(ctx, s.kubeClient, provider, commitStatus, s.tokenCache, s.notifierOptions()...)
+	}
+
+	key := string(provider.UID)
+	kind := provider.Kind
+	if kind == "" {
+		kind = apiv1beta3.ProviderKind
+	}
+	now := time.Now()
+	cached, err := s.notifierCache.Get(key)
+	hit := err == nil && cached.generation == provider.Generation
+	if hit && now.Sub(cached.checkedAt) < notifierSecretsRecheckInterval {
+		s.notifierCache.RecordCacheEvent(pkgcache.CacheEventTypeHit, kind, provider.Name, provider.Namespace)
+		return cached.notifier, cached.masker, nil
+	}
+
+	secretVersions, err := s.getProviderSecretVersions(ctx, provider)
+	if err != nil {
+		// Let the notifier creation report the error.
+		return createNotifier(ctx, s.kubeClient, provider, commitStatus, s.tokenCache, s.notifierOptions()...)
+	}
+
+	if hit && slices.Equal(cached.secretVersions, secretVersions) {
+		cached.checkedAt = now
+		_ = s.notifierCache.Set(key, cached)
+		s.notifierCache.RecordCacheEvent(pkgcache.CacheEventTypeHit, kind, provider.Name, provider.Namespace)
+		return cached.notifier, cached.masker, nil
+	}
+	s.notifierCache.RecordCacheEvent(pkgcache.CacheEventTypeMiss, kind, provider.Name, provider.Namespace)
+
+	sender, masker, err := createNotifier(ctx, s.kubeClient, provider, commitStatus, s.tokenCache, s.notifierOptions()...)
+	if err != nil {
+		_ = s.notifierCache.Delete(key)
+		return nil, masker, err
+	}
+
+	_ = s.notifierCache.Set(key, cachedNotifier{
+		notifier:       sender,
+		masker:         masker,
+		generation:     provider.Generation,
+		secretVersions: secretVersions,
+		checkedAt:      now,
+	})
+	return sender, masker, nil
+}
+
+// getProviderSecretVersions returns the resource versions of the Secrets
+// referenced by the given Provider.
+func (s *EventServer) getProviderSecretVersions(ctx context.Context, provider *apiv1beta3.Provider) ([]string, error) {
+	var versions []string
+	for _, ref := range []*meta.LocalObjectReference{provider.Spec.SecretRef, provider.Spec.CertSecretRef} {
+		if ref == nil {
+			versions = append(versions, "")
+			continue
+		}
+
+		var secret metav1.PartialObjectMetadata
+		secret.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Secret"))
+		if err := s.kubeClient.Get(ctx, types.NamespacedName{Namespace: provider.Namespace, Name: ref.Name}, &secret); err != nil {
+			return nil, err
+		}
+		versions = append(versions, string(secret.UID)+"/"+secret.ResourceVersion)
+	}
+	return versions, nil
+}
+
+// isCacheableProvider returns if the notifier of the given Provider can be
+// reused across events. The notifiers of the git providers depend on the
+// event commit status, and the GitHub notifiers may hold short-lived
+// GitHub App tokens.
+func isCacheableProvider(provider *apiv1beta3.Provider) bool {
+	return provider.UID != "" &&
+		!isGitProvider(provider.Spec.Type) &&
+		provider.Spec.Type != apiv1beta3.GitHubDispatchProvider
+}
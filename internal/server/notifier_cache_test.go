@@ -0,0 +1,112 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestGetNotifier(t *testing.T) {
+	g := NewWithT(t)
+
+	secret := &corev1.Secret{}
+	secret.Name = "secret-foo"
+	secret.Namespace = "foo-ns"
+	secret.Data = map[string][]byte{"address": []byte("https://example.com/hook")}
+
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "provider-foo"
+	provider.Namespace = "foo-ns"
+	provider.UID = "provider-uid"
+	provider.Generation = 1
+	provider.Spec = apiv1beta3.ProviderSpec{
+		Type:      apiv1beta3.GenericProvider,
+		SecretRef: &meta.LocalObjectReference{Name: secret.Name},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).ToNot(HaveOccurred())
+	g.Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
+	kubeClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	cache, err := NewNotifierCache(10)
+	g.Expect(err).ToNot(HaveOccurred())
+	s := &EventServer{
+		kubeClient:    kubeClient,
+		logger:        log.Log,
+		notifierCache: cache,
+	}
+	ctx := context.TODO()
+
+	// expireCheck makes the cached notifier due for a Secrets check.
+	expireCheck := func() {
+		cached, err := cache.Get(string(provider.UID))
+		g.Expect(err).ToNot(HaveOccurred())
+		cached.checkedAt = time.Now().Add(-notifierSecretsRecheckInterval)
+		g.Expect(cache.Set(string(provider.UID), cached)).To(Succeed())
+	}
+
+	n1, _, err := s.getNotifier(ctx, provider, "")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	n2, _, err := s.getNotifier(ctx, provider, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(n2).To(BeIdenticalTo(n1), "notifier reused")
+
+	expireCheck()
+	n3, _, err := s.getNotifier(ctx, provider, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(n3).To(BeIdenticalTo(n1), "notifier reused after checking unchanged Secrets")
+
+	secret.Data["address"] = []byte("https://example.com/rotated")
+	g.Expect(kubeClient.Update(ctx, secret)).To(Succeed())
+	n4, _, err := s.getNotifier(ctx, provider, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(n4).To(BeIdenticalTo(n1), "Secrets not checked before the recheck interval")
+
+	expireCheck()
+	n5, _, err := s.getNotifier(ctx, provider, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(n5).ToNot(BeIdenticalTo(n1), "notifier recreated after Secret change")
+
+	provider.Generation = 2
+	n6, _, err := s.getNotifier(ctx, provider, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(n6).ToNot(BeIdenticalTo(n5), "notifier recreated after Provider change")
+
+	g.Expect(kubeClient.Delete(ctx, secret)).To(Succeed())
+	provider.Generation = 3
+	_, _, err = s.getNotifier(ctx, provider, "")
+	g.Expect(err).To(MatchError(ContainSubstring("failed to read secret")))
+
+	gitProvider := provider.DeepCopy()
+	gitProvider.UID = "git-provider-uid"
+	gitProvider.Spec.Type = apiv1beta3.GitHubProvider
+	g.Expect(isCacheableProvider(gitProvider)).To(BeFalse())
+}
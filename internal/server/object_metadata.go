@@ -0,0 +1,156 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1"
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+// informerRetryInterval is the minimum interval between the attempts to
+// start the informer of a kind that can't be cached, e.g. as its CRD is not
+// installed yet.
+const informerRetryInterval = time.Minute
+
+// objectMetadataReader reads the metadata of the objects involved in events
+// from metadata-only informers, started for the kinds of the event sources
+// with label selectors. The objects of the kinds whose informer is not
+// synced are read from the API server.
+type objectMetadataReader struct {
+	cache     ctrlcache.Cache
+	apiReader client.Reader
+	logger    logr.Logger
+
+	mu sync.Mutex
+	// informers holds the started informers by kind.
+	informers map[schema.GroupVersionKind]ctrlcache.Informer
+	// failures holds the time of the last failed attempt to start the
+	// informer of the kinds that can't be cached.
+	failures map[schema.GroupVersionKind]time.Time
+}
+
+func newObjectMetadataReader(cache ctrlcache.Cache, apiReader client.Reader, logger logr.Logger) *objectMetadataReader {
+	return &objectMetadataReader{
+		cache:     cache,
+		apiReader: apiReader,
+		logger:    logger,
+		informers: make(map[schema.GroupVersionKind]ctrlcache.Informer),
+		failures:  make(map[schema.GroupVersionKind]time.Time),
+	}
+}
+
+// get reads the metadata of the object of the given kind and key, from the
+// cache when the informer of the kind is synced, from the API server
+// otherwise.
+func (r *objectMetadataReader) get(ctx context.Context, gvk schema.GroupVersionKind,
+	key client.ObjectKey) (*metav1.PartialObjectMetadata, error) {
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+
+	if informer := r.informerFor(gvk); informer != nil && informer.HasSynced() {
+		if err := r.cache.Get(ctx, key, obj); err == nil {
+			return obj, nil
+		}
+		// The object may not be in the cache yet, fall back to a live read.
+	}
+
+	if err := r.apiReader.Get(ctx, key, obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// informerFor returns the metadata-only informer of the given kind, starting
+// it without waiting for its sync if needed. It returns nil for the kinds
+// that can't be cached, for which the informer is started again at most
+// once per informerRetryInterval.
+func (r *objectMetadataReader) informerFor(gvk schema.GroupVersionKind) ctrlcache.Informer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if informer, ok := r.informers[gvk]; ok {
+		return informer
+	}
+	if failed, ok := r.failures[gvk]; ok && time.Since(failed) < informerRetryInterval {
+		return nil
+	}
+
+	obj := &metav1.PartialObjectMetadata{}
+	obj.SetGroupVersionKind(gvk)
+	informer, err := r.cache.GetInformer(context.Background(), obj, ctrlcache.BlockUntilSynced(false))
+	if err != nil {
+		r.logger.Error(err, "unable to start metadata informer, reading objects from the API server", "kind", gvk.String())
+		r.failures[gvk] = time.Now()
+		return nil
+	}
+	r.logger.V(1).Info("started metadata informer", "kind", gvk.String())
+	delete(r.failures, gvk)
+	r.informers[gvk] = informer
+	return informer
+}
+
+// watchAlertSources starts the metadata-only informers for the kinds of the
+// Alert and ClusterAlert event sources with label selectors and an API
+// version, as the Alerts are added or updated. The informers of the event
+// sources without an API version are started on their first event.
+func (r *objectMetadataReader) watchAlertSources(ctx context.Context) error {
+	handle := func(sources []apiv1.CrossNamespaceObjectReference) {
+		for _, source := range sources {
+			if source.MatchLabels == nil || source.APIVersion == "" {
+				continue
+			}
+			gv, err := schema.ParseGroupVersion(source.APIVersion)
+			if err != nil {
+				continue
+			}
+			r.informerFor(gv.WithKind(source.Kind))
+		}
+	}
+
+	for _, obj := range []client.Object{&apiv1beta3.Alert{}, &apiv1beta3.ClusterAlert{}} {
+		informer, err := r.cache.GetInformer(ctx, obj, ctrlcache.BlockUntilSynced(false))
+		if err != nil {
+			return err
+		}
+		onAlert := func(o any) {
+			switch alert := o.(type) {
+			case *apiv1beta3.Alert:
+				handle(alert.Spec.EventSources)
+			case *apiv1beta3.ClusterAlert:
+				handle(alert.Spec.EventSources)
+			}
+		}
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc:    onAlert,
+			UpdateFunc: func(_, o any) { onAlert(o) },
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
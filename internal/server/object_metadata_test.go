@@ -0,0 +1,184 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache/informertest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllertest"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1"
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+// fakeMetadataCache is a cache with fake informers, reading the objects
+// from the given reader.
+type fakeMetadataCache struct {
+	*informertest.FakeInformers
+	reader client.Reader
+	// err is returned for the metadata informers, when set.
+	err error
+}
+
+func (c *fakeMetadataCache) GetInformer(ctx context.Context, obj client.Object, opts ...cache.InformerGetOption) (cache.Informer, error) {
+	// The metadata informers don't need the kinds to be registered in
+	// the scheme.
+	if m, ok := obj.(*metav1.PartialObjectMetadata); ok {
+		if c.err != nil {
+			return nil, c.err
+		}
+		if c.InformersByGVK == nil {
+			c.InformersByGVK = map[schema.GroupVersionKind]toolscache.SharedIndexInformer{}
+		}
+		gvk := m.GroupVersionKind()
+		if _, ok := c.InformersByGVK[gvk]; !ok {
+			c.InformersByGVK[gvk] = &controllertest.FakeInformer{}
+		}
+		return c.InformersByGVK[gvk], nil
+	}
+	return c.FakeInformers.GetInformer(ctx, obj, opts...)
+}
+
+func (c *fakeMetadataCache) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return c.reader.Get(ctx, key, obj, opts...)
+}
+
+func TestObjectMetadataReader(t *testing.T) {
+	g := NewWithT(t)
+
+	// Any kind registered in the scheme can be read as metadata.
+	gvk := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	newObject := func(name, label string) *corev1.ConfigMap {
+		obj := &corev1.ConfigMap{}
+		obj.SetName(name)
+		obj.SetNamespace("foo-ns")
+		obj.SetLabels(map[string]string{"from": label})
+		return obj
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).ToNot(HaveOccurred())
+	g.Expect(corev1.AddToScheme(scheme)).ToNot(HaveOccurred())
+	cached := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(newObject("app", "cache")).Build()
+	live := fakeclient.NewClientBuilder().WithScheme(scheme).
+		WithObjects(newObject("app", "api"), newObject("new-app", "api")).Build()
+
+	informers := &informertest.FakeInformers{Scheme: scheme}
+	r := newObjectMetadataReader(&fakeMetadataCache{FakeInformers: informers, reader: cached}, live, log.Log)
+	ctx := context.TODO()
+
+	// The informer is started on the first read, before being synced.
+	obj, err := r.get(ctx, gvk, client.ObjectKey{Namespace: "foo-ns", Name: "app"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetLabels()).To(HaveKeyWithValue("from", "api"))
+	g.Expect(r.informers).To(HaveKeyWithValue(gvk, Not(BeNil())))
+
+	informer, err := informers.FakeInformerForKind(ctx, gvk)
+	g.Expect(err).ToNot(HaveOccurred())
+	informer.Synced = true
+
+	obj, err = r.get(ctx, gvk, client.ObjectKey{Namespace: "foo-ns", Name: "app"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetLabels()).To(HaveKeyWithValue("from", "cache"))
+
+	// Objects not in the cache yet are read from the API server.
+	obj, err = r.get(ctx, gvk, client.ObjectKey{Namespace: "foo-ns", Name: "new-app"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(obj.GetLabels()).To(HaveKeyWithValue("from", "api"))
+
+	_, err = r.get(ctx, gvk, client.ObjectKey{Namespace: "foo-ns", Name: "missing"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestObjectMetadataReader_informerFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	gvk := schema.GroupVersionKind{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Kind: "Kustomization"}
+	scheme := runtime.NewScheme()
+	c := &fakeMetadataCache{
+		FakeInformers: &informertest.FakeInformers{Scheme: scheme},
+		err:           errors.New("no matches for kind"),
+	}
+	r := newObjectMetadataReader(c, fakeclient.NewClientBuilder().WithScheme(scheme).Build(), log.Log)
+
+	// The failure is not retried before the retry interval.
+	g.Expect(r.informerFor(gvk)).To(BeNil())
+	c.err = nil
+	g.Expect(r.informerFor(gvk)).To(BeNil())
+	g.Expect(r.informers).To(BeEmpty())
+
+	// The informer is started once the kind can be cached.
+	r.failures[gvk] = time.Now().Add(-informerRetryInterval)
+	g.Expect(r.informerFor(gvk)).ToNot(BeNil())
+	g.Expect(r.informers).To(HaveKey(gvk))
+	g.Expect(r.failures).To(BeEmpty())
+}
+
+func TestObjectMetadataReader_watchAlertSources(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).ToNot(HaveOccurred())
+	informers := &informertest.FakeInformers{Scheme: scheme}
+	r := newObjectMetadataReader(&fakeMetadataCache{FakeInformers: informers},
+		fakeclient.NewClientBuilder().WithScheme(scheme).Build(), log.Log)
+	ctx := context.TODO()
+
+	g.Expect(r.watchAlertSources(ctx)).To(Succeed())
+
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert"
+	alert.Namespace = "foo-ns"
+	alert.Spec.EventSources = []apiv1.CrossNamespaceObjectReference{
+		{APIVersion: "kustomize.toolkit.fluxcd.io/v1", Kind: "Kustomization", Name: "*",
+			MatchLabels: map[string]string{"team": "a"}},
+		{APIVersion: "helm.toolkit.fluxcd.io/v2", Kind: "HelmRelease", Name: "*"},
+		{Kind: "GitRepository", Name: "*", MatchLabels: map[string]string{"team": "a"}},
+	}
+	alertInformer, err := informers.FakeInformerFor(ctx, &apiv1beta3.Alert{})
+	g.Expect(err).ToNot(HaveOccurred())
+	alertInformer.Add(alert)
+
+	clusterAlert := &apiv1beta3.ClusterAlert{}
+	clusterAlert.Name = "cluster-alert"
+	clusterAlert.Spec.EventSources = []apiv1.CrossNamespaceObjectReference{
+		{APIVersion: "source.toolkit.fluxcd.io/v1", Kind: "Bucket", Name: "*",
+			MatchLabels: map[string]string{"team": "a"}},
+	}
+	clusterAlertInformer, err := informers.FakeInformerFor(ctx, &apiv1beta3.ClusterAlert{})
+	g.Expect(err).ToNot(HaveOccurred())
+	clusterAlertInformer.Add(clusterAlert)
+
+	g.Expect(r.informers).To(HaveLen(2))
+	g.Expect(r.informers).To(HaveKey(schema.GroupVersionKind{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Kind: "Kustomization"}))
+	g.Expect(r.informers).To(HaveKey(schema.GroupVersionKind{Group: "source.toolkit.fluxcd.io", Version: "v1", Kind: "Bucket"}))
+}
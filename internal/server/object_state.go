@@ -0,0 +1,209 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+)
+
+const (
+	// objectStateTTL is the time after which an object is forgotten when
+	// no new event is received for it, e.g. when the object or the alert has
+	// been deleted.
+	objectStateTTL = 24 * time.Hour
+	// objectStatePruneInterval is the minimum interval between the removals
+	// of the expired failing objects.
+	objectStatePruneInterval = 10 * time.Minute
+)
+
+// objectStateTracker keeps track of the state of the involved objects for
+// an alert, i.e. whether the last event dispatched for the alert had the
+// error severity. The state is kept in memory and is lost on restarts.
+type objectStateTracker struct {
+	mu        sync.Mutex
+	objects   map[objectStateKey]*objectState
+	lastPrune time.Time
+}
+
+// objectStateKey identifies an involved object for an alert.
+type objectStateKey struct {
+	alert  string
+	object string
+}
+
+type objectState struct {
+	failing  bool
+	since    time.Time
+	lastSeen time.Time
+	event    eventv1.Event
+}
+
+// objectTransition is the change of state of an involved object observed
+// for an alert.
+type objectTransition int
+
+const (
+	// objectUnchanged is returned when the event doesn't mark the recovery
+	// of the object.
+	objectUnchanged objectTransition = iota
+	// objectResolved is returned when the object recovered from a failure
+	// dispatched for the alert.
+	objectResolved
+	// objectUntracked is returned when the event marks the success of an
+	// object with an unknown state, e.g. after a restart or when its failure
+	// expired, which may still resolve a failure dispatched before.
+	objectUntracked
+)
+
+func newObjectStateTracker() *objectStateTracker {
+	return &objectStateTracker{
+		objects: make(map[objectStateKey]*objectState),
+	}
+}
+
+func newObjectStateKey(alert *apiv1beta3.Alert, event *eventv1.Event) objectStateKey {
+	return objectStateKey{
+		alert:  fmt.Sprintf("%s/%s/%s", alert.Kind, alert.Namespace, alert.Name),
+		object: involvedObjectString(event.InvolvedObject),
+	}
+}
+
+// resolves returns if the given event marks the recovery of the involved
+// object from a failure previously dispatched for the given alert.
+func (t *objectStateTracker) resolves(alert *apiv1beta3.Alert, event *eventv1.Event) bool {
+	if t == nil || !isRecoveryEvent(event) {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	o, ok := t.objects[newObjectStateKey(alert, event)]
+	return ok && o.failing
+}
+
+// observe records the state of the involved object of the given event
+// dispatched for the given alert, and returns a resolution if the event
+// marks the recovery of the object from a failure previously dispatched for
+// the alert.
+//
+// Error events mark the object as failing. Info events mark the object as
+// recovered, unless they report progress or a commit status update, as these
+// don't mean that the object has been reconciled successfully. Trace events
+// are ignored.
+func (t *objectStateTracker) observe(alert *apiv1beta3.Alert, event *eventv1.Event) (notifier.Resolution, objectTransition) {
+	if t == nil {
+		return notifier.Resolution{}, objectUnchanged
+	}
+
+	key := newObjectStateKey(alert, event)
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(now)
+
+	o, ok := t.objects[key]
+	switch {
+	case event.Severity == eventv1.EventSeverityError:
+		since := event.Timestamp.Time
+		if ok && o.failing {
+			since = o.since
+		}
+		t.objects[key] = &objectState{
+			failing:  true,
+			since:    since,
+			lastSeen: now,
+			event:    *event.DeepCopy(),
+		}
+	case isRecoveryEvent(event):
+		t.objects[key] = &objectState{lastSeen: now}
+		switch {
+		case !ok:
+			return notifier.Resolution{}, objectUntracked
+		case o.failing:
+			return notifier.Resolution{
+				FailedEvent:  o.event,
+				FailingSince: o.since,
+			}, objectResolved
+		}
+	}
+	return notifier.Resolution{}, objectUnchanged
+}
+
+// restore marks the involved object of the given event as failing again for
+// the given alert, after the notification of its recovery with the given
+// resolution failed to be dispatched, so that the next recovery event
+// resolves the failure. It does nothing if a failure has been observed since.
+func (t *objectStateTracker) restore(alert *apiv1beta3.Alert, event *eventv1.Event, r notifier.Resolution) {
+	if t == nil {
+		return
+	}
+
+	key := newObjectStateKey(alert, event)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if o, ok := t.objects[key]; ok && o.failing {
+		return
+	}
+	t.objects[key] = &objectState{
+		failing:  true,
+		since:    r.FailingSince,
+		lastSeen: time.Now(),
+		event:    r.FailedEvent,
+	}
+}
+
+// prune removes the objects without events for longer than objectStateTTL,
+// at most once per objectStatePruneInterval.
+func (t *objectStateTracker) prune(now time.Time) {
+	if now.Sub(t.lastPrune) < objectStatePruneInterval {
+		return
+	}
+	t.lastPrune = now
+	for key, o := range t.objects {
+		if now.Sub(o.lastSeen) >= objectStateTTL {
+			delete(t.objects, key)
+		}
+	}
+}
+
+// isRecoveryEvent returns if the given event can mark the recovery of its
+// involved object.
+func isRecoveryEvent(event *eventv1.Event) bool {
+	return event.Severity == eventv1.EventSeverityInfo &&
+		!event.HasReason(meta.ProgressingReason) && !isCommitStatusUpdate(event)
+}
+
+// isCommitStatusUpdate returns if the given event, with its metadata keys
+// still prefixed with the involved object's API Group, is a Git commit
+// status update.
+func isCommitStatusUpdate(event *eventv1.Event) bool {
+	objectGroup := event.InvolvedObject.GetObjectKind().GroupVersionKind().Group
+	key := fmt.Sprintf("%s/%s", objectGroup, eventv1.MetaCommitStatusKey)
+	return event.HasMetadata(key, eventv1.MetaCommitStatusUpdateValue)
+}
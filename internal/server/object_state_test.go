@@ -0,0 +1,264 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+)
+
+func TestObjectStateTracker(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newEvent := func(name, severity, reason string, minutes int) *eventv1.Event {
+		return &eventv1.Event{
+			InvolvedObject: corev1.ObjectReference{
+				APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+				Kind:       "Kustomization",
+				Name:       name,
+				Namespace:  "default",
+			},
+			Severity:  severity,
+			Reason:    reason,
+			Message:   reason,
+			Timestamp: metav1.NewTime(start.Add(time.Duration(minutes) * time.Minute)),
+		}
+	}
+
+	alert := &apiv1beta3.Alert{
+		TypeMeta:   metav1.TypeMeta{Kind: apiv1beta3.AlertKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "alert", Namespace: "default"},
+	}
+
+	tests := []struct {
+		name           string
+		events         []*eventv1.Event
+		wantResolved   []bool
+		wantUntracked  []bool
+		wantFailedMsg  string
+		wantFailingFor time.Duration
+	}{
+		{
+			name: "success without failure is not a resolution",
+			events: []*eventv1.Event{
+				newEvent("app", eventv1.EventSeverityInfo, meta.SucceededReason, 0),
+				newEvent("app", eventv1.EventSeverityInfo, meta.SucceededReason, 5),
+			},
+			wantResolved:  []bool{false, false},
+			wantUntracked: []bool{true, false},
+		},
+		{
+			name: "success after failures is a resolution",
+			events: []*eventv1.Event{
+				newEvent("app", eventv1.EventSeverityError, "HealthCheckFailed", 0),
+				newEvent("app", eventv1.EventSeverityError, meta.FailedReason, 5),
+				newEvent("app", eventv1.EventSeverityInfo, meta.SucceededReason, 10),
+				newEvent("app", eventv1.EventSeverityInfo, meta.SucceededReason, 15),
+			},
+			wantResolved:   []bool{false, false, true, false},
+			wantFailedMsg:  meta.FailedReason,
+			wantFailingFor: 10 * time.Minute,
+		},
+		{
+			name: "progressing and trace events are not a resolution",
+			events: []*eventv1.Event{
+				newEvent("app", eventv1.EventSeverityError, meta.FailedReason, 0),
+				newEvent("app", eventv1.EventSeverityInfo, meta.ProgressingReason, 1),
+				newEvent("app", eventv1.EventSeverityTrace, meta.SucceededReason, 2),
+				newEvent("app", eventv1.EventSeverityInfo, meta.SucceededReason, 3),
+			},
+			wantResolved:   []bool{false, false, false, true},
+			wantFailedMsg:  meta.FailedReason,
+			wantFailingFor: 3 * time.Minute,
+		},
+		{
+			name: "commit status update is not a resolution",
+			events: []*eventv1.Event{
+				newEvent("app", eventv1.EventSeverityError, meta.FailedReason, 0),
+				func() *eventv1.Event {
+					e := newEvent("app", eventv1.EventSeverityInfo, meta.SucceededReason, 1)
+					e.Metadata = map[string]string{
+						"kustomize.toolkit.fluxcd.io/" + eventv1.MetaCommitStatusKey: eventv1.MetaCommitStatusUpdateValue,
+					}
+					return e
+				}(),
+			},
+			wantResolved: []bool{false, false},
+		},
+		{
+			name: "objects are tracked separately",
+			events: []*eventv1.Event{
+				newEvent("app", eventv1.EventSeverityError, meta.FailedReason, 0),
+				newEvent("other", eventv1.EventSeverityInfo, meta.SucceededReason, 1),
+			},
+			wantResolved:  []bool{false, false},
+			wantUntracked: []bool{false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tracker := newObjectStateTracker()
+			for i, event := range tt.events {
+				g.Expect(tracker.resolves(alert, event)).To(Equal(tt.wantResolved[i]), "event %d", i)
+				r, transition := tracker.observe(alert, event)
+				g.Expect(transition == objectResolved).To(Equal(tt.wantResolved[i]), "event %d", i)
+				untracked := tt.wantUntracked != nil && tt.wantUntracked[i]
+				g.Expect(transition == objectUntracked).To(Equal(untracked), "event %d", i)
+				if transition == objectResolved {
+					g.Expect(r.FailedEvent.Message).To(Equal(tt.wantFailedMsg))
+					g.Expect(event.Timestamp.Sub(r.FailingSince)).To(Equal(tt.wantFailingFor))
+				}
+			}
+		})
+	}
+}
+
+func TestObjectStateTracker_alerts(t *testing.T) {
+	g := NewWithT(t)
+
+	newAlert := func(name string) *apiv1beta3.Alert {
+		return &apiv1beta3.Alert{
+			TypeMeta:   metav1.TypeMeta{Kind: apiv1beta3.AlertKind},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+	}
+	newEvent := func(severity, reason string) *eventv1.Event {
+		return &eventv1.Event{
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Kustomization",
+				Name:      "app",
+				Namespace: "default",
+			},
+			Severity:  severity,
+			Reason:    reason,
+			Timestamp: metav1.Now(),
+		}
+	}
+	errorAlert, infoAlert := newAlert("errors"), newAlert("infos")
+
+	// The failure is dispatched only for the alert of the error events.
+	tracker := newObjectStateTracker()
+	_, transition := tracker.observe(errorAlert, newEvent(eventv1.EventSeverityError, meta.FailedReason))
+	g.Expect(transition).To(Equal(objectUnchanged))
+
+	// The recovery resolves the failure only for that alert.
+	recovery := newEvent(eventv1.EventSeverityInfo, meta.SucceededReason)
+	g.Expect(tracker.resolves(infoAlert, recovery)).To(BeFalse())
+	_, transition = tracker.observe(infoAlert, recovery)
+	g.Expect(transition).To(Equal(objectUntracked))
+	g.Expect(tracker.resolves(errorAlert, recovery)).To(BeTrue())
+	_, transition = tracker.observe(errorAlert, recovery)
+	g.Expect(transition).To(Equal(objectResolved))
+	g.Expect(tracker.resolves(errorAlert, recovery)).To(BeFalse())
+}
+
+func TestObjectStateTracker_prune(t *testing.T) {
+	g := NewWithT(t)
+
+	alert := &apiv1beta3.Alert{
+		TypeMeta:   metav1.TypeMeta{Kind: apiv1beta3.AlertKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "alert", Namespace: "default"},
+	}
+	newEvent := func(name string) *eventv1.Event {
+		return &eventv1.Event{
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Kustomization",
+				Name:      name,
+				Namespace: "default",
+			},
+			Severity:  eventv1.EventSeverityError,
+			Reason:    meta.FailedReason,
+			Timestamp: metav1.Now(),
+		}
+	}
+
+	tracker := newObjectStateTracker()
+	tracker.observe(alert, newEvent("deleted"))
+	tracker.observe(alert, newEvent("failing"))
+	g.Expect(tracker.objects).To(HaveLen(2))
+
+	// The objects without events for longer than the TTL are removed.
+	tracker.mu.Lock()
+	for key, o := range tracker.objects {
+		if key.object == "Kustomization/default/deleted" {
+			o.lastSeen = o.lastSeen.Add(-objectStateTTL)
+		}
+	}
+	tracker.lastPrune = time.Time{}
+	tracker.mu.Unlock()
+
+	tracker.observe(alert, newEvent("failing"))
+	g.Expect(tracker.objects).To(HaveLen(1))
+	g.Expect(tracker.objects).To(HaveKey(objectStateKey{
+		alert:  "Alert/default/alert",
+		object: "Kustomization/default/failing",
+	}))
+}
+
+func TestObjectStateTracker_restore(t *testing.T) {
+	g := NewWithT(t)
+
+	alert := &apiv1beta3.Alert{
+		TypeMeta:   metav1.TypeMeta{Kind: apiv1beta3.AlertKind},
+		ObjectMeta: metav1.ObjectMeta{Name: "alert", Namespace: "default"},
+	}
+	newEvent := func(severity, reason string) *eventv1.Event {
+		return &eventv1.Event{
+			InvolvedObject: corev1.ObjectReference{
+				Kind:      "Kustomization",
+				Name:      "app",
+				Namespace: "default",
+			},
+			Severity:  severity,
+			Reason:    reason,
+			Message:   reason,
+			Timestamp: metav1.Now(),
+		}
+	}
+
+	tracker := newObjectStateTracker()
+	tracker.observe(alert, newEvent(eventv1.EventSeverityError, meta.FailedReason))
+	recovery := newEvent(eventv1.EventSeverityInfo, meta.SucceededReason)
+	r, transition := tracker.observe(alert, recovery)
+	g.Expect(transition).To(Equal(objectResolved))
+
+	// The failure is resolved again by the next recovery event once the
+	// notification of the resolution failed.
+	tracker.restore(alert, recovery, r)
+	restored, transition := tracker.observe(alert, recovery)
+	g.Expect(transition).To(Equal(objectResolved))
+	g.Expect(restored).To(Equal(r))
+
+	// A failure observed since is kept.
+	tracker.observe(alert, newEvent(eventv1.EventSeverityError, "HealthCheckFailed"))
+	tracker.restore(alert, recovery, r)
+	latest, transition := tracker.observe(alert, recovery)
+	g.Expect(transition).To(Equal(objectResolved))
+	g.Expect(latest.FailedEvent.Message).To(Equal("HealthCheckFailed"))
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"cmp"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/fluxcd/pkg/git/github"
+)
+
+// minMaskedSecretLength is the minimum length of the secret values masked,
+// so that short values, e.g. "true", don't garble the messages.
+const minMaskedSecretLength = 4
+
+// maskedSecretValue replaces the secret values in the masked messages.
+const maskedSecretValue = "*****"
+
+// secretCredentialKeys are the keys of the Secret of a Provider holding
+// credentials, or addresses with embedded credentials, masked as a whole.
+// The username and password are also the SASL credentials of the AMQP and
+// Kafka providers, and the credentials of the MQTT and SMTP providers.
+var secretCredentialKeys = []string{
+	"address",
+	"token",
+	"username",
+	"password",
+	"accessKeyID",
+	"secretAccessKey",
+	"sessionToken",
+	github.AppIDKey,
+	github.AppInstallationIDKey,
+	github.AppPrivateKey,
+}
+
+// secretMasker masks the credentials of the Secret of a Provider in the
+// errors of its notifier.
+type secretMasker struct {
+	// pattern matches exactly the credentials, longest first so that a
+	// credential isn't partially masked by one of its substrings.
+	pattern *regexp.Regexp
+}
+
+// newSecretMasker returns a secretMasker masking the credential values of
+// the given Secret, the values of the headers, and the password and query
+// parameter values of the address and proxy URLs. The other values, e.g.
+// the channel, are not masked.
+func newSecretMasker(secret *corev1.Secret) secretMasker {
+	var m secretMasker
+	if secret == nil {
+		return m
+	}
+
+	var values []string
+	add := func(value string) {
+		if value = strings.TrimSpace(value); len(value) >= minMaskedSecretLength {
+			values = append(values, value)
+		}
+	}
+
+	for _, key := range secretCredentialKeys {
+		if data, ok := secret.Data[key]; ok {
+			add(string(data))
+		}
+	}
+
+	if data, ok := secret.Data["headers"]; ok {
+		headers := make(map[string]string)
+		if err := yaml.Unmarshal(data, &headers); err == nil {
+			for _, v := range headers {
+				add(v)
+			}
+		}
+	}
+
+	for _, key := range []string{"address", "proxy"} {
+		u, err := url.Parse(strings.TrimSpace(string(secret.Data[key])))
+		if err != nil || u.Host == "" {
+			continue
+		}
+		if password, ok := u.User.Password(); ok {
+			add(password)
+		}
+		for _, vs := range u.Query() {
+			for _, v := range vs {
+				add(v)
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return m
+	}
+	slices.SortFunc(values, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+	values = slices.Compact(values)
+	for i, value := range values {
+		values[i] = regexp.QuoteMeta(value)
+	}
+	m.pattern = regexp.MustCompile(strings.Join(values, "|"))
+	return m
+}
+
+// mask returns the given string with the secret values masked.
+func (m secretMasker) mask(s string) string {
+	if m.pattern == nil {
+		return s
+	}
+	return m.pattern.ReplaceAllLiteralString(s, maskedSecretValue)
+}
+
+// maskedError is an error with the secret values masked in its message,
+// wrapping the original error so that its type, e.g. the Retry-After of the
+// errors of the providers, can still be inspected.
+type maskedError struct {
+	msg string
+	err error
+}
+
+func (e *maskedError) Error() string {
+	return e.msg
+}
+
+func (e *maskedError) Unwrap() error {
+	return e.err
+}
+
+// maskError returns the given error with the secret values masked in its
+// message.
+func (m secretMasker) maskError(err error) error {
+	if err == nil || m.pattern == nil {
+		return err
+	}
+	msg := err.Error()
+	if masked := m.mask(msg); masked != msg {
+		return &maskedError{msg: masked, err: err}
+	}
+	return err
+}
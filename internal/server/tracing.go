@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+const tracerName = "github.com/fluxcd/notification-controller/internal/server"
+
+// startSpan starts a span named after the given operation of the event
+// server, using the global tracer provider.
+func startSpan(ctx context.Context, operation string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, "notification."+operation, opts...)
+}
+
+// endSpan ends the given span, recording the given error.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// eventAttributes returns the span attributes of the given event.
+func eventAttributes(event *eventv1.Event) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("event.involved_object.kind", event.InvolvedObject.Kind),
+		attribute.String("event.involved_object.namespace", event.InvolvedObject.Namespace),
+		attribute.String("event.involved_object.name", event.InvolvedObject.Name),
+		attribute.String("event.severity", event.Severity),
+		attribute.String("event.reason", event.Reason),
+	}
+}
+
+// providerAttributes returns the span attributes of the given Provider.
+func providerAttributes(provider providerReference) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("provider.kind", provider.Kind),
+		attribute.String("provider.namespace", provider.Namespace),
+		attribute.String("provider.name", provider.Name),
+	}
+}
+
+// detachedSpanContext returns a context without the deadline and values of
+// the given context, but with its span, for tracing the work done after the
+// event has been handled.
+func detachedSpanContext(ctx context.Context) context.Context {
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
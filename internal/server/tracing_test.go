@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	log "sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/apis/meta"
+
+	apiv1 "github.com/fluxcd/notification-controller/api/v1"
+	apiv1beta3 "github.com/fluxcd/notification-controller/api/v1beta3"
+	"github.com/fluxcd/notification-controller/internal/index"
+)
+
+func TestEventServer_tracing(t *testing.T) {
+	g := NewWithT(t)
+
+	spans := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	// The trace context is propagated to the provider.
+	traceparents := make(chan string, 1)
+	rcvServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents <- r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer rcvServer.Close()
+
+	testNamespace := "foo-ns"
+	provider := &apiv1beta3.Provider{}
+	provider.Name = "webhook"
+	provider.Namespace = testNamespace
+	provider.Spec = apiv1beta3.ProviderSpec{Type: apiv1beta3.GenericProvider, Address: rcvServer.URL}
+
+	alert := &apiv1beta3.Alert{}
+	alert.Name = "alert"
+	alert.Namespace = testNamespace
+	alert.Spec = apiv1beta3.AlertSpec{
+		ProviderRef:   &meta.LocalObjectReference{Name: provider.Name},
+		EventSeverity: eventv1.EventSeverityInfo,
+		EventSources: []apiv1.CrossNamespaceObjectReference{
+			{Kind: "Kustomization", Name: "*"},
+		},
+	}
+
+	scheme := runtime.NewScheme()
+	g.Expect(apiv1beta3.AddToScheme(scheme)).To(Succeed())
+	g.Expect(corev1.AddToScheme(scheme)).To(Succeed())
+	eventServer := &EventServer{
+		kubeClient: fakeclient.NewClientBuilder().WithScheme(scheme).
+			WithObjects(alert, provider).
+			WithIndex(&apiv1beta3.Alert{}, index.AlertEventSourceKey, index.AlertEventSources).
+			WithIndex(&apiv1beta3.ClusterAlert{}, index.ClusterAlertEventSourceKey, index.ClusterAlertEventSources).
+			Build(),
+		logger:        log.Log,
+		EventRecorder: record.NewFakeRecorder(32),
+		objectStates:  newObjectStateTracker(),
+	}
+
+	body, err := json.Marshal(eventv1.Event{
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "kustomize.toolkit.fluxcd.io/v1",
+			Kind:       "Kustomization",
+			Name:       "foo",
+			Namespace:  testNamespace,
+		},
+		Severity: eventv1.EventSeverityInfo,
+		Reason:   "ReconciliationSucceeded",
+		Message:  "applied",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	eventServer.eventMiddleware(http.HandlerFunc(eventServer.handleEvent())).ServeHTTP(rec, req)
+	g.Expect(rec.Code).To(Equal(http.StatusAccepted))
+
+	var traceparent string
+	g.Eventually(traceparents).Should(Receive(&traceparent))
+	g.Expect(traceparent).To(HavePrefix("00-" + traceID + "-"))
+
+	wantSpans := []string{
+		"notification.event.receive",
+		"notification.alerts.filter",
+		"notification.event.metadata.combine",
+		"notification.commitstatus.evaluate",
+		"notification.notifier.create",
+		"notification.provider.send",
+	}
+	g.Eventually(func() []string {
+		var names []string
+		for _, span := range spans.Ended() {
+			g.Expect(span.SpanContext().TraceID().String()).To(Equal(traceID))
+			names = append(names, span.Name())
+		}
+		return names
+	}).Should(ContainElements(wantSpans))
+}
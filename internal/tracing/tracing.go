@@ -0,0 +1,91 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing sets up the export of the OpenTelemetry traces of
+// notification-controller to an OTLP endpoint.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	flagOTLPEndpoint = "otlp-endpoint"
+	flagSampleRatio  = "otlp-sample-ratio"
+)
+
+// Options contains the configuration of the traces export.
+type Options struct {
+	// Endpoint is the URL of the OTLP/HTTP endpoint to which the traces are
+	// exported, e.g. http://otel-collector:4318. Tracing is disabled when
+	// empty.
+	Endpoint string
+	// SampleRatio is the ratio of the traces sampled, for the traces not
+	// started by the senders of the events.
+	SampleRatio float64
+}
+
+// BindFlags will parse the given pflag.FlagSet for tracing option flags and
+// set the Options accordingly.
+func (o *Options) BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Endpoint, flagOTLPEndpoint, "",
+		"The URL of the OTLP/HTTP endpoint to which the traces are exported, if empty tracing is disabled.")
+	fs.Float64Var(&o.SampleRatio, flagSampleRatio, 1,
+		"The ratio of the traces sampled, for the events received without a sampled trace context.")
+}
+
+// Setup registers the global OpenTelemetry tracer provider exporting the
+// traces to the configured endpoint, and the W3C trace context propagator.
+// The returned function flushes the pending traces and stops the export.
+// Without endpoint, nothing is registered, the trace context of the received
+// events is neither extracted nor propagated, and the returned function is a
+// no-op.
+func Setup(ctx context.Context, serviceName string, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+	return provider.Shutdown, nil
+}
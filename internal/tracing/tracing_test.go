@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetup(t *testing.T) {
+	g := NewWithT(t)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	// A stand-in for an OTLP collector.
+	var exported atomic.Int32
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/traces" {
+			body, _ := io.ReadAll(r.Body)
+			if len(body) > 0 {
+				exported.Add(1)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	shutdown, err := Setup(context.Background(), "notification-controller", Options{
+		Endpoint:    collector.URL,
+		SampleRatio: 1,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(otel.GetTextMapPropagator().Fields()).To(ContainElement("traceparent"))
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test")
+	g.Expect(span.SpanContext().IsSampled()).To(BeTrue())
+	span.End()
+
+	g.Expect(shutdown(context.Background())).To(Succeed())
+	g.Expect(exported.Load()).To(Equal(int32(1)))
+}
+
+func TestSetup_disabled(t *testing.T) {
+	g := NewWithT(t)
+
+	shutdown, err := Setup(context.Background(), "notification-controller", Options{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(otel.GetTextMapPropagator().Fields()).To(BeEmpty())
+
+	_, span := otel.Tracer("test").Start(context.Background(), "test")
+	g.Expect(span.SpanContext().IsValid()).To(BeFalse())
+	span.End()
+	g.Expect(shutdown(context.Background())).To(Succeed())
+}
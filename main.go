@@ -17,6 +17,7 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -53,7 +54,10 @@ import (
 	apiv1b3 "github.com/fluxcd/notification-controller/api/v1beta3"
 	"github.com/fluxcd/notification-controller/internal/controller"
 	"github.com/fluxcd/notification-controller/internal/features"
+	"github.com/fluxcd/notification-controller/internal/notifier"
+	"github.com/fluxcd/notification-controller/internal/policy"
 	"github.com/fluxcd/notification-controller/internal/server"
+	"github.com/fluxcd/notification-controller/internal/tracing"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -75,7 +79,8 @@ func init() {
 
 func main() {
 	const (
-		tokenCacheDefaultMaxSize = 0
+		tokenCacheDefaultMaxSize    = 0
+		notifierCacheDefaultMaxSize = 256
 	)
 
 	var (
@@ -94,6 +99,13 @@ func main() {
 		featureGates          feathelper.FeatureGates
 		exportHTTPPathMetrics bool
 		tokenCacheOptions     pkgcache.TokenFlags
+		notifierCacheMaxSize  int
+		dispatcherOptions     server.DispatcherOptions
+		circuitBreakerOptions server.CircuitBreakerOptions
+		tracingOptions        tracing.Options
+		auditSink             string
+		egressPolicyOptions   notifier.EgressPolicyOptions
+		providerTypePolicy    string
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
@@ -105,6 +117,39 @@ func main() {
 		"Watch for custom resources in all namespaces, if set to false it will only watch the runtime namespace.")
 	flag.DurationVar(&rateLimitInterval, "rate-limit-interval", 5*time.Minute, "Interval in which rate limit has effect.")
 	flag.BoolVar(&exportHTTPPathMetrics, "export-http-path-metrics", false, "When enabled, the requests full path is included in the HTTP server metrics (risk as high cardinality")
+	flag.IntVar(&notifierCacheMaxSize, "notifier-cache-max-size", notifierCacheDefaultMaxSize,
+		"The maximum number of Provider notifiers reused across events, if set to 0 the notifiers are created for every event.")
+	flag.IntVar(&dispatcherOptions.Concurrency, "dispatch-concurrency", 64,
+		"The maximum number of notifications sent at once.")
+	flag.IntVar(&dispatcherOptions.QueueSize, "dispatch-queue-size", 4096,
+		"The maximum number of notifications waiting to be sent, above which events are rejected with HTTP 503.")
+	flag.IntVar(&dispatcherOptions.ProviderConcurrency, "provider-concurrency", 4,
+		"The maximum number of notifications sent at once to the same Provider.")
+	flag.Float64Var(&dispatcherOptions.ProviderRateLimit, "provider-rate-limit", 0,
+		"The maximum number of notifications sent per second to the same Provider, if set to 0 the rate is not limited.")
+	flag.IntVar(&dispatcherOptions.ProviderRateBurst, "provider-rate-burst", 10,
+		"The number of notifications sent at once to the same Provider above the rate limit.")
+	flag.IntVar(&circuitBreakerOptions.FailureThreshold, "provider-failure-threshold", 5,
+		"The number of consecutive failures after which the notifications to a Provider are skipped, if set to 0 the notifications are never skipped.")
+	flag.DurationVar(&circuitBreakerOptions.OpenTimeout, "provider-circuit-open-timeout", time.Minute,
+		"The duration for which the notifications to a failing Provider are skipped, before probing it again.")
+
+	flag.StringVar(&auditSink, "audit-sink", "",
+		"The sink of the audit records of the notifications, either stdout, file:<path> or provider:<namespace>/<name>, if empty no audit record is written.")
+
+	flag.StringSliceVar(&egressPolicyOptions.AllowCIDRs, "egress-allow-cidrs", nil,
+		"The networks the Providers can connect to, including private networks. If set, the connections to other addresses are denied.")
+	flag.StringSliceVar(&egressPolicyOptions.DenyCIDRs, "egress-deny-cidrs", nil,
+		"The networks the Providers can't connect to.")
+	flag.StringSliceVar(&egressPolicyOptions.AllowHosts, "egress-allow-hosts", nil,
+		"The host names the Providers can connect to, with '*.' matching the subdomains. If set, the connections to other addresses are denied.")
+	flag.StringSliceVar(&egressPolicyOptions.DenyHosts, "egress-deny-hosts", nil,
+		"The host names the Providers can't connect to, with '*.' matching the subdomains.")
+	flag.BoolVar(&egressPolicyOptions.BlockPrivateNetworks, "egress-block-private-networks", false,
+		"Deny the connections of the Providers to private, loopback and link-local addresses, unless explicitly allowed.")
+
+	flag.StringVar(&providerTypePolicy, "provider-type-policy", "",
+		"The path of the YAML file restricting the Provider types allowed in the namespaces, if empty all types are allowed.")
 
 	clientOptions.BindFlags(flag.CommandLine)
 	logOptions.BindFlags(flag.CommandLine)
@@ -113,6 +158,7 @@ func main() {
 	rateLimiterOptions.BindFlags(flag.CommandLine)
 	featureGates.BindFlags(flag.CommandLine)
 	tokenCacheOptions.BindFlags(flag.CommandLine, tokenCacheDefaultMaxSize)
+	tracingOptions.BindFlags(flag.CommandLine)
 
 	flag.Parse()
 
@@ -123,6 +169,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	egressPolicy, err := notifier.NewEgressPolicy(egressPolicyOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to load the egress policy")
+		os.Exit(1)
+	}
+
+	var typePolicy *policy.ProviderTypePolicy
+	if providerTypePolicy != "" {
+		p, err := policy.LoadProviderTypePolicy(providerTypePolicy)
+		if err != nil {
+			setupLog.Error(err, "unable to load the provider type policy")
+			os.Exit(1)
+		}
+		typePolicy = p
+	}
+
 	watchNamespace := ""
 	if !watchAllNamespaces {
 		watchNamespace = os.Getenv("RUNTIME_NAMESPACE")
@@ -183,9 +245,10 @@ func main() {
 	metricsH := helper.NewMetrics(mgr, metrics.MustMakeRecorder(), apiv1.NotificationFinalizer)
 
 	if err = (&controller.ProviderReconciler{
-		Client:         mgr.GetClient(),
-		ControllerName: controllerName,
-		EventRecorder:  mgr.GetEventRecorderFor(controllerName),
+		Client:             mgr.GetClient(),
+		ControllerName:     controllerName,
+		EventRecorder:      mgr.GetEventRecorderFor(controllerName),
+		ProviderTypePolicy: typePolicy,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Provider")
 		os.Exit(1)
@@ -214,6 +277,17 @@ func main() {
 	// +kubebuilder:scaffold:builder
 
 	ctx := ctrl.SetupSignalHandler()
+	shutdownTracing, err := tracing.Setup(ctx, controllerName, tracingOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
+
 	store, err := memorystore.New(&memorystore.Config{
 		Interval: rateLimitInterval,
 	})
@@ -235,6 +309,36 @@ func main() {
 		}
 	}
 
+	eventServerOpts := []server.EventServerOption{
+		server.WithClusterResourceNamespace(os.Getenv("RUNTIME_NAMESPACE")),
+		server.WithMetricsRegisterer(ctrlmetrics.Registry),
+		server.WithObjectMetadataCache(mgr.GetCache(), mgr.GetAPIReader()),
+		server.WithDispatcherOptions(dispatcherOptions),
+		server.WithProviderTypePolicy(typePolicy),
+		server.WithEgressPolicy(egressPolicy),
+	}
+	if auditSink != "" {
+		auditOpt, err := server.WithAuditSink(auditSink)
+		if err != nil {
+			setupLog.Error(err, "unable to create audit sink")
+			os.Exit(1)
+		}
+		eventServerOpts = append(eventServerOpts, auditOpt)
+	}
+	if circuitBreakerOptions.FailureThreshold > 0 {
+		eventServerOpts = append(eventServerOpts, server.WithCircuitBreakerOptions(circuitBreakerOptions))
+	}
+	if notifierCacheMaxSize > 0 {
+		notifierCache, err := server.NewNotifierCache(notifierCacheMaxSize,
+			pkgcache.WithMetricsRegisterer(ctrlmetrics.Registry),
+			pkgcache.WithMetricsPrefix("gotk_notifier_"))
+		if err != nil {
+			setupLog.Error(err, "unable to create notifier cache")
+			os.Exit(1)
+		}
+		eventServerOpts = append(eventServerOpts, server.WithNotifierCache(notifierCache))
+	}
+
 	setupLog.Info("starting event server", "addr", eventsAddr)
 	eventMdlw := middleware.New(middleware.Config{
 		Recorder: prommetrics.NewRecorder(prommetrics.Config{
@@ -242,7 +346,8 @@ func main() {
 			Registry: ctrlmetrics.Registry,
 		}),
 	})
-	eventServer := server.NewEventServer(eventsAddr, ctrl.Log, mgr.GetClient(), mgr.GetEventRecorderFor(controllerName), aclOptions.NoCrossNamespaceRefs, exportHTTPPathMetrics, tokenCache)
+	eventServer := server.NewEventServer(eventsAddr, ctrl.Log, mgr.GetClient(), mgr.GetEventRecorderFor(controllerName),
+		aclOptions.NoCrossNamespaceRefs, exportHTTPPathMetrics, tokenCache, eventServerOpts...)
 	go eventServer.ListenAndServe(ctx.Done(), eventMdlw, store)
 
 	setupLog.Info("starting webhook receiver server", "addr", receiverAddr)